@@ -2,19 +2,79 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"github.com/nchagrass/mars-exploration/internal/bootstrap"
+	"os"
 )
 
-var defaultInputPath = "./test/inputsample-1.txt"
+var defaultInputPath = "./test/golden/sample-1.input"
 
 func main() {
-	var path string
-	flag.StringVar(&path,
+	if len(os.Args) > 1 {
+		if command, ok := bootstrap.Commands[os.Args[1]]; ok {
+			os.Exit(command(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
+	var opts bootstrap.Options
+	flag.StringVar(&opts.InputPath,
 		"input-path",
 		defaultInputPath,
 		"default input path to read instructions from",
 	)
+	flag.BoolVar(&opts.Optimize,
+		"optimize",
+		false,
+		"print the mission with the shortest instructions giving the same trajectories instead of exploring it",
+	)
+	flag.StringVar(&opts.Restore,
+		"restore",
+		"",
+		"path of a snapshot to resume instead of reading the mission from -input-path",
+	)
+	flag.StringVar(&opts.Snapshot,
+		"snapshot",
+		"",
+		"path to write the snapshot of the exploration to once done",
+	)
+	flag.DurationVar(&opts.Timeout,
+		"timeout",
+		0,
+		"stop the exploration once elapsed, ie: 5s (no timeout by default)",
+	)
+	flag.IntVar(&opts.MaxSteps,
+		"max-steps",
+		0,
+		"stop the exploration once as many instructions were executed (no limit by default)",
+	)
+	flag.BoolVar(&opts.Parallel,
+		"parallel",
+		false,
+		"explore robots speculatively on every CPU, giving the same results as the sequential exploration",
+	)
+	flag.StringVar(&opts.Metrics,
+		"metrics",
+		"",
+		`path of a JSON file to dump the mission metrics to once done, "-" for standard error`,
+	)
+	flag.BoolVar(&opts.Map,
+		"map",
+		false,
+		"print the map of the explored surface instead of the report",
+	)
+	flag.BoolVar(&opts.Watch,
+		"watch",
+		false,
+		"explore the mission again every time -input-path changes, redrawing the report or the map until ctrl+c",
+	)
+	config := bootstrap.NewConfigFlags(flag.CommandLine)
 	flag.Parse()
 
-	bootstrap.New(path)
+	var err error
+	if opts.Config, err = config.Config(os.LookupEnv); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	bootstrap.New(opts)
 }
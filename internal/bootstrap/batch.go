@@ -0,0 +1,174 @@
+package bootstrap
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	goldenInput    = ".input"
+	goldenExpected = ".expected"
+)
+
+// Golden is a mission along with the report expected from it, found as <name>.input and <name>.expected files
+type Golden struct {
+	Name     string
+	Input    string
+	Expected string
+	// Config holds the limits and policies the mission is explored with, the defaults when zero
+	Config Config
+}
+
+// GoldenResult is the outcome of checking a Golden
+type GoldenResult struct {
+	Golden Golden
+	// Got is the report of the mission
+	Got string
+	// Err is set when the mission can't be explored or its expected report can't be read or written
+	Err error
+	// Diff is the unified diff from the expected report to the one of the mission, empty when they are the same
+	Diff string
+}
+
+// Passed tells if the mission gave the expected report
+func (r GoldenResult) Passed() bool {
+	return r.Err == nil && r.Diff == ""
+}
+
+// FindGolden returns every <name>.input file of a directory along with its <name>.expected file, sorted by name
+func FindGolden(dir string) ([]Golden, error) {
+	inputs, err := filepath.Glob(filepath.Join(dir, "*"+goldenInput))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(inputs)
+
+	goldens := make([]Golden, 0, len(inputs))
+	for _, input := range inputs {
+		name := strings.TrimSuffix(input, goldenInput)
+		goldens = append(goldens, Golden{
+			Name:     filepath.Base(name),
+			Input:    input,
+			Expected: name + goldenExpected,
+		})
+	}
+
+	return goldens, nil
+}
+
+// Check explores the mission and compares its text report with the expected one
+// with update the expected report is written instead, the check always passing then
+func (g Golden) Check(update bool) GoldenResult {
+	result := GoldenResult{Golden: g}
+
+	me, err := load(g.Input, g.Config, newLogger(ioutil.Discard))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	me.SendInstructions()
+
+	var got bytes.Buffer
+	domain.Reporter{Explorer: me}.Fprint(&got)
+	result.Got = got.String()
+
+	if update {
+		if err := ioutil.WriteFile(g.Expected, got.Bytes(), 0644); err != nil {
+			result.Err = fmt.Errorf(`unable to write expected report to path "%s" - got %q`, g.Expected, err)
+		}
+		return result
+	}
+
+	want, err := ioutil.ReadFile(g.Expected)
+	if err != nil {
+		result.Err = fmt.Errorf(`unable to read expected report from path "%s" - got %q`, g.Expected, err)
+		return result
+	}
+	result.Diff = unifiedDiff(filepath.Base(g.Expected), g.Name+" (got)", string(want), result.Got)
+
+	return result
+}
+
+// CheckGolden checks goldens in parallel, results being in the same order as goldens
+func CheckGolden(goldens []Golden, update bool) []GoldenResult {
+	results := make([]GoldenResult, len(goldens))
+	forEach(len(goldens), 0, func(i int) {
+		results[i] = goldens[i].Check(update)
+	})
+
+	return results
+}
+
+// Batch runs every mission of a directory and compares their report with the expected ones (see Golden)
+// it prints a pass/fail table followed by the diff of every failure and exits with 1 when any failed
+func Batch(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("batch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dir := fs.String("dir", "./test/golden", "directory of the <name>.input and <name>.expected files")
+	update := fs.Bool("update", false, "write the expected reports instead of checking them")
+	config := NewConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	c, err := config.Config(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if info, err := os.Stat(*dir); err != nil || !info.IsDir() {
+		fmt.Fprintf(stderr, "%s is not a directory\n", *dir)
+		return 2
+	}
+
+	goldens, err := FindGolden(*dir)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	for i := range goldens {
+		goldens[i].Config = c
+	}
+	results := CheckGolden(goldens, *update)
+
+	failed := 0
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "STATUS\tMISSION\t")
+	for _, r := range results {
+		status := "PASS"
+		switch {
+		case !r.Passed():
+			status = "FAIL"
+			failed++
+		case *update:
+			status = "UPDATED"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t\n", status, r.Golden.Name)
+	}
+	tw.Flush()
+
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(stdout, "\n%s: %s\n", r.Golden.Name, r.Err)
+		} else if r.Diff != "" {
+			fmt.Fprintf(stdout, "\n%s", r.Diff)
+		}
+	}
+
+	fmt.Fprintf(stdout, "\n%d passed, %d failed\n", len(results)-failed, failed)
+	if failed > 0 {
+		return 1
+	}
+
+	return 0
+}
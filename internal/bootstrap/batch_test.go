@@ -0,0 +1,70 @@
+package bootstrap
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "batch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"pass.input":      "5 3\n1 1 E\nRFRFRFRF\n",
+		"pass.expected":   "1 1 E\n",
+		"fail.input":      "5 3\n3 2 N\nFRRFLLFF\n",
+		"fail.expected":   "3 3 N\n",
+		"broken.input":    "5 3\n1 1 E\nFXF\n",
+		"missing.input":   "5 3\n1 1 E\nF\n",
+		"ignored.txt":     "5 3\n1 1 E\nF\n",
+		"orphan.expected": "1 1 E\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var stdout bytes.Buffer
+	code := Batch([]string{"-dir", dir}, &stdout, ioutil.Discard)
+	if code != 1 {
+		t.Errorf("Batch() got code %d, want 1", code)
+	}
+	for _, want := range []string{
+		"STATUS  MISSION  \nFAIL    broken   \nFAIL    fail     \nFAIL    missing  \nPASS    pass     \n",
+		"--- fail.expected\n+++ fail (got)\n@@ -1,1 +1,1 @@\n-3 3 N\n+3 3 N LOST\n",
+		"broken: failed to prepare the exploration",
+		`missing: unable to read expected report from path`,
+		"1 passed, 3 failed\n",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("Batch() got %q, want it to contain %q", stdout.String(), want)
+		}
+	}
+
+	stdout.Reset()
+	if code := Batch([]string{"-dir", dir, "-update"}, &stdout, ioutil.Discard); code != 1 {
+		t.Errorf("Batch() -update got code %d, want 1 as broken can't be explored", code)
+	}
+	got, _ := ioutil.ReadFile(filepath.Join(dir, "fail.expected"))
+	if string(got) != "3 3 N LOST\n" {
+		t.Errorf("Batch() -update wrote %q", got)
+	}
+
+	stdout.Reset()
+	os.Remove(filepath.Join(dir, "broken.input"))
+	if code := Batch([]string{"-dir", dir}, &stdout, ioutil.Discard); code != 0 {
+		t.Errorf("Batch() got code %d after updating, want 0, %s", code, stdout.String())
+	}
+
+	if code := Batch([]string{"-dir", filepath.Join(dir, "missing")}, &stdout, ioutil.Discard); code != 2 {
+		t.Errorf("Batch() got code %d for a missing directory, want 2", code)
+	}
+}
@@ -1,29 +1,216 @@
 package bootstrap
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"github.com/nchagrass/mars-exploration/internal/domain"
 	"github.com/sirupsen/logrus"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
 )
 
+// Options holds the settings of a mission run
+type Options struct {
+	InputPath string
+	// Config holds the limits, policies, output format and logging, the defaults when zero
+	Config Config
+	// Optimize prints the mission with optimized instructions instead of exploring it
+	Optimize bool
+	// Restore is the path of a snapshot to resume instead of reading the mission from InputPath
+	Restore string
+	// Snapshot is the path to write the snapshot of the exploration to once done
+	Snapshot string
+	// Timeout stops the exploration once elapsed, 0 meaning no timeout
+	Timeout time.Duration
+	// MaxSteps stops the exploration once as many instructions were executed, 0 meaning no limit
+	MaxSteps int
+	// Parallel explores robots speculatively on every CPU, see domain.MarsExplorer.SendInstructionsParallel
+	Parallel bool
+	// Metrics is the path of a JSON file to dump the mission metrics to once done, "-" for standard error
+	Metrics string
+	// Map prints the map of the explored surface instead of the report (see domain.MarsExplorer.Map)
+	Map bool
+	// Watch explores the mission again every time InputPath changes, see Watcher
+	Watch bool
+}
+
 // Bootstrap initialise the project
-func New(path string) {
-	logger := logrus.New()
-	logger.SetFormatter(&logrus.JSONFormatter{})
+func New(opts Options) {
+	logger := opts.Config.logger(os.Stderr)
 
-	setup, err := NewFileInstructions(path)
-	if err != nil {
-		logger.Fatalf(`unable to read instructions from path "%s" - got %q`, path, err)
+	if opts.Watch {
+		if opts.Restore != "" || opts.Snapshot != "" || opts.Optimize ||
+			opts.Parallel || opts.MaxSteps > 0 || opts.Timeout > 0 || opts.Metrics != "" {
+			logger.Fatal("watch mode can't be combined with restore, snapshot, optimize, parallel, max-steps, timeout or metrics")
+		}
+		ctx, cancel := interruptible(0)
+		defer cancel()
+		Watcher{Path: opts.InputPath, Config: opts.Config, Map: opts.Map, Out: os.Stdout}.Watch(ctx)
+		return
 	}
 
 	// load mars grid / robots
-	builder := domain.NewMarsBuilder(logger)
-	me, err := builder.Build(setup)
+	var me *domain.MarsExplorer
+	var err error
+	if opts.Restore != "" {
+		me, err = restore(opts.Restore)
+	} else {
+		me, err = build(opts.InputPath, opts.Config.builder(logger))
+	}
+	if err != nil {
+		logger.Fatal(err)
+	}
+	// a restored mission doesn't come with a logger
+	me.SetLogger(domain.NewLogrusLogger(logger))
+
+	if opts.Optimize {
+		optimize(me, os.Stdout, os.Stderr)
+		return
+	}
+
+	metrics := domain.NewMetrics(nil)
+	if opts.Parallel {
+		if opts.Timeout > 0 || opts.MaxSteps > 0 {
+			logger.Fatal("parallel exploration can't be stopped, it can't be combined with a timeout or a step budget")
+		}
+		metrics.Explore(me, func() {
+			reexecuted := me.SendInstructionsParallel(0)
+			logger.WithField("reexecuted", reexecuted).Debug("robots explored again after reading a scent left by an earlier robot")
+		})
+	} else {
+		ctx, cancel := interruptible(opts.Timeout)
+		defer cancel()
+		metrics.Explore(me, func() {
+			progress, err := me.SendInstructionsContext(ctx, opts.MaxSteps)
+			if err != nil {
+				interrupted(logger, me, progress, err)
+			}
+		})
+	}
+
+	if opts.Snapshot != "" {
+		if err := snapshot(me, opts.Snapshot); err != nil {
+			logger.Fatal(err)
+		}
+	}
+
+	if opts.Map {
+		fmt.Println(strings.Join(me.Map(), "\n"))
+	} else {
+		reporter, err := domain.NewReporter(opts.Config.Format, me)
+		if err != nil {
+			logger.Fatalf("unable to report the exploration, %q", err)
+		}
+		reporter.Print()
+	}
+
+	if opts.Metrics != "" {
+		if err := dumpMetrics(metrics, opts.Metrics, os.Stderr); err != nil {
+			logger.Fatal(err)
+		}
+	}
+}
+
+// dumpMetrics writes the metrics as JSON to a file, "-" writing them to stderr
+func dumpMetrics(metrics *domain.Metrics, path string, stderr io.Writer) error {
+	data, err := json.MarshalIndent(metrics.Stats(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("unable to dump metrics, %q", err)
+	}
+	data = append(data, '\n')
+
+	if path == "-" {
+		_, err = stderr.Write(data)
+		return err
+	}
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf(`unable to write metrics to path "%s" - got %q`, path, err)
+	}
+
+	return nil
+}
+
+// interruptible returns a context done on interrupt signal (ctrl+c) or once the timeout, if any, elapsed
+func interruptible(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		select {
+		case <-signals:
+			cancel()
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+
+	return ctx, cancel
+}
+
+// interrupted logs where an exploration stopped before completion
+func interrupted(logger *logrus.Logger, me *domain.MarsExplorer, progress *domain.Progress, err error) {
+	running := make([]string, 0, len(progress.Running))
+	for _, r := range progress.Running {
+		running = append(running, me.Robots[r].Label(r))
+	}
+
+	entry := logger.WithFields(logrus.Fields{
+		"steps":    progress.Steps,
+		"finished": len(progress.Finished),
+		"running":  strings.Join(running, ","),
+	})
+	if progress.Last != nil {
+		entry = entry.WithField("last", progress.Last.String())
+	}
+	entry.Warnf("exploration interrupted, %q", err)
+}
+
+// optimize writes the mission with optimized instructions and how many instructions were saved for each robot
+func optimize(me *domain.MarsExplorer, stdout, stderr io.Writer) {
+	optimized := me.Optimize()
+
+	fmt.Fprint(stdout, strings.Join(optimized.Mission(), "\n"))
+	for i := range me.Robots {
+		fmt.Fprintf(stderr, "robot %s: %d -> %d instructions\n",
+			me.Robots[i].Label(i), len(strings.Join(me.Robots[i].Instructions, "")), len(strings.Join(optimized.Robots[i].Instructions, "")))
+	}
+}
+
+// restore reads a snapshot file and restores its MarsExplorer
+func restore(path string) (*domain.MarsExplorer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf(`unable to read snapshot from path "%s" - got %q`, path, err)
+	}
+
+	me, err := domain.Restore(data)
 	if err != nil {
-		logrus.Fatalf("failed to prepare the exploration, %q", err)
+		return nil, fmt.Errorf("failed to restore the exploration, %q", err)
 	}
 
-	me.SendInstructions()
+	return me, nil
+}
+
+// snapshot writes the snapshot of a MarsExplorer to a file
+func snapshot(me *domain.MarsExplorer, path string) error {
+	data, err := me.Snapshot()
+	if err != nil {
+		return fmt.Errorf("failed to snapshot the exploration, %q", err)
+	}
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf(`unable to write snapshot to path "%s" - got %q`, path, err)
+	}
 
-	reporter := domain.Reporter{Explorer: me}
-	reporter.Print()
+	return nil
 }
@@ -0,0 +1,124 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_optimize(t *testing.T) {
+	l := logrus.New()
+	l.SetOutput(ioutil.Discard)
+	me, err := load("../../test/golden/sample-1.input", Config{}, l)
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	optimize(me, &stdout, &stderr)
+
+	wantMission := "5 3\n1 1 E\nRFRFRFRF\n\n3 2 N\nFRRFRRFF\n\n0 3 W\nRRFFFRRFL\n"
+	if stdout.String() != wantMission {
+		t.Errorf("optimize() got mission %q, want %q", stdout.String(), wantMission)
+	}
+	wantReport := "robot #1: 8 -> 8 instructions\nrobot #2: 13 -> 8 instructions\nrobot #3: 10 -> 9 instructions\n"
+	if stderr.String() != wantReport {
+		t.Errorf("optimize() got report %q, want %q", stderr.String(), wantReport)
+	}
+}
+
+func Test_snapshot_restore(t *testing.T) {
+	l := logrus.New()
+	l.SetOutput(ioutil.Discard)
+	me, err := load("../../test/golden/sample-1.input", Config{}, l)
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+	me.SendInstructions()
+
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "snapshot.json")
+
+	if err := snapshot(me, path); err != nil {
+		t.Fatalf("snapshot() error = %v", err)
+	}
+
+	restored, err := restore(path)
+	if err != nil {
+		t.Fatalf("restore() error = %v", err)
+	}
+	if !reflect.DeepEqual(restored.Scents, me.Scents) {
+		t.Errorf("restore() got scents %v, want %v", restored.Scents, me.Scents)
+	}
+	for i := range me.Robots {
+		if restored.Robots[i].ToString() != me.Robots[i].ToString() {
+			t.Errorf("restore() got robot %s, want %s", restored.Robots[i].ToString(), me.Robots[i].ToString())
+		}
+	}
+
+	if _, err := restore(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("restore() expected an error for a missing snapshot")
+	}
+}
+
+func Test_interrupted(t *testing.T) {
+	l := logrus.New()
+	l.SetOutput(ioutil.Discard)
+	me, err := load("../../test/golden/sample-1.input", Config{}, l)
+	if err != nil {
+		t.Fatalf("load() error = %v", err)
+	}
+
+	ctx, cancel := interruptible(time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	progress, err := me.SendInstructionsContext(ctx, 0)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("SendInstructionsContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	var logs bytes.Buffer
+	l.SetOutput(&logs)
+	interrupted(l, me, progress, err)
+
+	want := `running="#1,#2,#3" steps=0`
+	if !strings.Contains(logs.String(), want) {
+		t.Errorf("interrupted() got %s, want it to contain %s", logs.String(), want)
+	}
+}
+
+func Test_load_eventLogs(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   Config
+		wantLogs bool
+	}{
+		{name: "default level", config: DefaultConfig()},
+		{name: "debug level", config: Config{LogLevel: "debug"}, wantLogs: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			me, err := load("../../test/golden/sample-1.input", tt.config, tt.config.logger(&logs))
+			if err != nil {
+				t.Fatalf("load() error = %v", err)
+			}
+			me.SendInstructions()
+
+			if got := strings.Contains(logs.String(), "robot lost"); got != tt.wantLogs {
+				t.Errorf("SendInstructions() got logs %q, want robot lost logged %t", logs.String(), tt.wantLogs)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package bootstrap
+
+import (
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"github.com/sirupsen/logrus"
+	"io"
+)
+
+// Command is a subcommand of the app, it receives its own arguments and returns the process exit code
+type Command func(args []string, stdout, stderr io.Writer) int
+
+// Commands lists the app subcommands by name
+var Commands = map[string]Command{
+	"batch":    Batch,
+	"config":   ConfigCommand,
+	"cover":    Cover,
+	"debug":    Debug,
+	"fmt":      Fmt,
+	"lint":     Lint,
+	"plan":     Plan,
+	"repl":     Repl,
+	"run":      RunMissions,
+	"serve":    Serve,
+	"validate": Validate,
+}
+
+// newLogger returns the logger shared by the app commands
+func newLogger(out io.Writer) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	logger.SetOutput(out)
+
+	return logger
+}
+
+// load reads a mission file and builds its MarsExplorer with the limits and policies of the config
+func load(path string, config Config, logger *logrus.Logger) (*domain.MarsExplorer, error) {
+	return build(path, config.builder(logger))
+}
+
+// build reads a mission file and builds its MarsExplorer
+func build(path string, builder domain.MarsBuilder) (*domain.MarsExplorer, error) {
+	setup, err := NewFileInstructions(path)
+	if err != nil {
+		return nil, fmt.Errorf(`unable to read instructions from path "%s" - got %q`, path, err)
+	}
+
+	me, err := builder.Build(setup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare the exploration, %q", err)
+	}
+
+	return me, nil
+}
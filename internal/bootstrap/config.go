@@ -0,0 +1,366 @@
+package bootstrap
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+
+	// configEnv is the environment variable giving the config file when -config isn't
+	configEnv = "MARS_CONFIG"
+	// envPrefix prefixes the environment variable of every setting, ie: MARS_LOG_LEVEL for log.level
+	envPrefix = "MARS_"
+)
+
+// Config holds the simulator defaults and policies, its zero value being the defaults
+// it is read from a TOML config file, environment variables then flags, each one overriding the previous ones
+type Config struct {
+	// MaxCoordinate is the maximum value for any coordinate of the grid, domain.MaxCoordinate when 0
+	MaxCoordinate int
+	// MaxInstructions is the maximum length of an instruction line, domain.MaxInstructions when 0
+	MaxInstructions int
+	Rules           domain.Rules
+	// Format is the report output format, one of domain.FormatText (default) or domain.FormatJSON
+	Format string
+	// LogLevel is a logrus level, info when empty
+	LogLevel string
+	// LogFormat is LogFormatJSON (default) or LogFormatText
+	LogFormat string
+}
+
+// setting is a Config field as written in a config file ("<section>.<name>"), as an environment variable and as a flag
+type setting struct {
+	key   string
+	flag  string
+	usage string
+	get   func(c *Config) string
+	set   func(c *Config, v string) error
+}
+
+// settings lists every Config field, in the order of the config file
+var settings = []setting{
+	{
+		key: "grid.max_coordinate", flag: "max-coordinate", usage: "maximum value for any coordinate of the grid",
+		get: func(c *Config) string { return strconv.Itoa(c.MaxCoordinate) },
+		set: func(c *Config, v string) (err error) { c.MaxCoordinate, err = strconv.Atoi(v); return err },
+	},
+	{
+		key: "instructions.max_length", flag: "max-instructions", usage: "maximum length of an instruction line",
+		get: func(c *Config) string { return strconv.Itoa(c.MaxInstructions) },
+		set: func(c *Config, v string) (err error) { c.MaxInstructions, err = strconv.Atoi(v); return err },
+	},
+	{
+		key: "simulation.edge_policy", flag: "edge-policy", usage: "what happens to a robot moving off the grid, lost or stop",
+		get: func(c *Config) string { return c.Rules.Edge },
+		set: func(c *Config, v string) error { c.Rules.Edge = v; return nil },
+	},
+	{
+		key: "simulation.scent_mode", flag: "scent-mode", usage: "how scents are left and honoured, pose, cell or off",
+		get: func(c *Config) string { return c.Rules.Scent },
+		set: func(c *Config, v string) error { c.Rules.Scent = v; return nil },
+	},
+	{
+		key: "output.format", flag: "format", usage: "report output format, text or json",
+		get: func(c *Config) string { return c.Format },
+		set: func(c *Config, v string) error { c.Format = v; return nil },
+	},
+	{
+		key: "log.level", flag: "log-level", usage: "log level, ie: debug, info, warning or error",
+		get: func(c *Config) string { return c.LogLevel },
+		set: func(c *Config, v string) error { c.LogLevel = v; return nil },
+	},
+	{
+		key: "log.format", flag: "log-format", usage: "log format, json or text",
+		get: func(c *Config) string { return c.LogFormat },
+		set: func(c *Config, v string) error { c.LogFormat = v; return nil },
+	},
+}
+
+// env returns the environment variable of the setting, ie: MARS_LOG_LEVEL for log.level
+func (s setting) env() string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(s.key, ".", "_"))
+}
+
+// DefaultConfig returns the defaults, the same as the zero Config
+func DefaultConfig() Config {
+	return Config{
+		MaxCoordinate:   domain.MaxCoordinate,
+		MaxInstructions: domain.MaxInstructions,
+		Rules:           domain.Rules{Edge: domain.EdgeLost, Scent: domain.ScentPose},
+		Format:          domain.FormatText,
+		LogLevel:        logrus.InfoLevel.String(),
+		LogFormat:       LogFormatJSON,
+	}
+}
+
+// Validate returns an error for the first invalid setting
+func (c Config) Validate() error {
+	if c.MaxCoordinate < 0 {
+		return fmt.Errorf("grid.max_coordinate can't be negative, got %d", c.MaxCoordinate)
+	}
+	if c.MaxInstructions < 0 {
+		return fmt.Errorf("instructions.max_length can't be negative, got %d", c.MaxInstructions)
+	}
+	if err := c.Rules.Validate(); err != nil {
+		return fmt.Errorf("simulation: %s", err)
+	}
+	if _, err := domain.NewReporter(c.Format, nil); err != nil {
+		return fmt.Errorf("output.format: %s", err)
+	}
+	if c.LogLevel != "" {
+		if _, err := logrus.ParseLevel(c.LogLevel); err != nil {
+			return fmt.Errorf("log.level: %s", err)
+		}
+	}
+	switch c.LogFormat {
+	case "", LogFormatJSON, LogFormatText:
+	default:
+		return fmt.Errorf("log.format: unsupported log format %s, expected %s or %s", c.LogFormat, LogFormatJSON, LogFormatText)
+	}
+
+	return nil
+}
+
+// ReadConfigFile applies the settings of a TOML config file, ie:
+//
+//	[simulation]
+//	edge_policy = "stop"
+//
+// only sections, key = value pairs of strings, integers or booleans and comments are supported
+func (c *Config) ReadConfigFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf(`unable to read config from path "%s" - got %q`, path, err)
+	}
+	defer f.Close()
+
+	if err := c.readTOML(f); err != nil {
+		return fmt.Errorf("invalid config %s, %s", path, err)
+	}
+
+	return nil
+}
+
+// readTOML applies the settings of a TOML document
+func (c *Config) readTOML(r io.Reader) error {
+	section := ""
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return fmt.Errorf("line %d: expected a section as [name], got %s", n, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("line %d: expected key = value, got %s", n, line)
+		}
+		key := strings.TrimSpace(kv[0])
+		if section != "" {
+			key = section + "." + key
+		}
+
+		value := strings.TrimSpace(kv[1])
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return fmt.Errorf("line %d: invalid string %s", n, value)
+			}
+			value = unquoted
+		}
+
+		if err := c.set(key, value); err != nil {
+			return fmt.Errorf("line %d: %s", n, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+// stripComment removes a # comment from a TOML line, unless within a string
+func stripComment(line string) string {
+	quoted := false
+	for i, r := range line {
+		switch {
+		case r == '"' && (i == 0 || line[i-1] != '\\'):
+			quoted = !quoted
+		case r == '#' && !quoted:
+			return line[:i]
+		}
+	}
+
+	return line
+}
+
+// set applies a setting given its key
+func (c *Config) set(key, value string) error {
+	for _, s := range settings {
+		if s.key == key {
+			if err := s.set(c, value); err != nil {
+				return fmt.Errorf("invalid %s %q", key, value)
+			}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown setting %s", key)
+}
+
+// ApplyEnv applies the settings given as environment variables, ie: MARS_SIMULATION_SCENT_MODE=cell
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	for _, s := range settings {
+		if v, ok := lookup(s.env()); ok {
+			if err := s.set(c, v); err != nil {
+				return fmt.Errorf("invalid %s %q", s.env(), v)
+			}
+		}
+	}
+
+	return nil
+}
+
+// TOML returns the config as a TOML document which can be read back as a config file
+func (c Config) TOML() string {
+	var sb strings.Builder
+	section := ""
+	for _, s := range settings {
+		parts := strings.SplitN(s.key, ".", 2)
+		if parts[0] != section {
+			if section != "" {
+				sb.WriteString("\n")
+			}
+			section = parts[0]
+			fmt.Fprintf(&sb, "[%s]\n", section)
+		}
+
+		value := s.get(&c)
+		if _, err := strconv.Atoi(value); err != nil {
+			value = strconv.Quote(value)
+		}
+		fmt.Fprintf(&sb, "%s = %s # %s\n", parts[1], value, s.env())
+	}
+
+	return sb.String()
+}
+
+// builder returns a MarsBuilder applying the limits and policies of the config
+func (c Config) builder(logger *logrus.Logger) domain.MarsBuilder {
+	builder := domain.NewMarsBuilder(domain.NewLogrusLogger(logger))
+	builder.SetLimits(c.MaxCoordinate, c.MaxInstructions)
+	builder.SetRules(c.Rules)
+
+	return builder
+}
+
+// logger returns a logger writing to out with the level and format of the config
+func (c Config) logger(out io.Writer) *logrus.Logger {
+	logger := newLogger(out)
+	if level, err := logrus.ParseLevel(c.LogLevel); err == nil {
+		logger.SetLevel(level)
+	}
+	if c.LogFormat == LogFormatText {
+		logger.SetFormatter(&logrus.TextFormatter{})
+	}
+
+	return logger
+}
+
+// ConfigFlags are the flags of every Config setting, along with -config giving the config file
+type ConfigFlags struct {
+	fs     *flag.FlagSet
+	path   *string
+	values map[string]*string
+}
+
+// NewConfigFlags registers the config flags on a flag set
+func NewConfigFlags(fs *flag.FlagSet) *ConfigFlags {
+	f := &ConfigFlags{
+		fs:     fs,
+		path:   fs.String("config", "", "TOML config file, "+configEnv+" when not given"),
+		values: make(map[string]*string),
+	}
+	for _, s := range settings {
+		f.values[s.flag] = fs.String(s.flag, "", fmt.Sprintf("%s (%s, %s in the config file)", s.usage, s.env(), s.key))
+	}
+
+	return f
+}
+
+// Config returns the config once the flags are parsed: the defaults, overridden by the config file,
+// then by the environment variables and then by the flags given
+func (f *ConfigFlags) Config(lookup func(string) (string, bool)) (Config, error) {
+	c := DefaultConfig()
+
+	path := *f.path
+	if path == "" {
+		path, _ = lookup(configEnv)
+	}
+	if path != "" {
+		if err := c.ReadConfigFile(path); err != nil {
+			return c, err
+		}
+	}
+
+	if err := c.ApplyEnv(lookup); err != nil {
+		return c, err
+	}
+
+	var err error
+	f.fs.Visit(func(fl *flag.Flag) {
+		for _, s := range settings {
+			if s.flag == fl.Name && err == nil {
+				if serr := s.set(&c, fl.Value.String()); serr != nil {
+					err = fmt.Errorf("invalid -%s %q", s.flag, fl.Value.String())
+				}
+			}
+		}
+	})
+	if err != nil {
+		return c, err
+	}
+
+	return c, c.Validate()
+}
+
+// ConfigCommand prints the config given the config file, environment variables and flags: config print [flags]
+func ConfigCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(stderr, "usage: config print [-config file] [flags]")
+		return 2
+	}
+
+	fs := flag.NewFlagSet("config print", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	flags := NewConfigFlags(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	c, err := flags.Config(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	fmt.Fprint(stdout, c.TOML())
+
+	return 0
+}
@@ -0,0 +1,300 @@
+package bootstrap
+
+import (
+	"bytes"
+	"flag"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfig_readTOML(t *testing.T) {
+	tests := []struct {
+		name    string
+		toml    string
+		want    Config
+		wantErr string
+	}{
+		{
+			name: "every setting",
+			toml: `# mission defaults
+[grid]
+max_coordinate = 80
+
+[instructions]
+max_length = 200 # uplink cap
+
+[simulation]
+edge_policy = "stop"
+scent_mode = "cell"
+
+[output]
+format = "json"
+
+[log]
+level = "debug"
+format = "text"
+`,
+			want: Config{
+				MaxCoordinate:   80,
+				MaxInstructions: 200,
+				Rules:           domain.Rules{Edge: domain.EdgeStop, Scent: domain.ScentCell},
+				Format:          domain.FormatJSON,
+				LogLevel:        "debug",
+				LogFormat:       LogFormatText,
+			},
+		},
+		{
+			name: "dotted keys",
+			toml: "simulation.scent_mode = \"off\"\n",
+			want: Config{Rules: domain.Rules{Scent: domain.ScentOff}},
+		},
+		{
+			name: "comment character within a string",
+			toml: "[log]\nlevel = \"#\"\n",
+			want: Config{LogLevel: "#"},
+		},
+		{
+			name:    "unknown setting",
+			toml:    "[grid]\nmax_x = 5\n",
+			wantErr: "line 2: unknown setting grid.max_x",
+		},
+		{
+			name:    "invalid integer",
+			toml:    "[grid]\nmax_coordinate = \"fifty\"\n",
+			wantErr: `line 2: invalid grid.max_coordinate "fifty"`,
+		},
+		{
+			name:    "missing value",
+			toml:    "[grid]\nmax_coordinate\n",
+			wantErr: "line 2: expected key = value, got max_coordinate",
+		},
+		{
+			name:    "unclosed section",
+			toml:    "[grid\n",
+			wantErr: "line 1: expected a section as [name], got [grid",
+		},
+		{
+			name:    "unterminated string",
+			toml:    "[log]\nlevel = \"debug\n",
+			wantErr: `line 2: invalid string "debug`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Config
+			err := got.readTOML(strings.NewReader(tt.toml))
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("readTOML() got error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readTOML() got error %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("readTOML() got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr string
+	}{
+		{name: "zero", config: Config{}},
+		{name: "defaults", config: DefaultConfig()},
+		{
+			name:    "negative grid limit",
+			config:  Config{MaxCoordinate: -1},
+			wantErr: "grid.max_coordinate can't be negative, got -1",
+		},
+		{
+			name:    "negative instructions limit",
+			config:  Config{MaxInstructions: -1},
+			wantErr: "instructions.max_length can't be negative, got -1",
+		},
+		{
+			name:    "edge policy",
+			config:  Config{Rules: domain.Rules{Edge: "wrap"}},
+			wantErr: "simulation: unsupported edge policy wrap, expected lost or stop",
+		},
+		{
+			name:    "output format",
+			config:  Config{Format: "xml"},
+			wantErr: "output.format: ",
+		},
+		{
+			name:    "log level",
+			config:  Config{LogLevel: "verbose"},
+			wantErr: "log.level: ",
+		},
+		{
+			name:    "log format",
+			config:  Config{LogFormat: "logfmt"},
+			wantErr: "log.format: unsupported log format logfmt, expected json or text",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() got error %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() got error %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigFlags_Config(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "mars.toml")
+	toml := "[simulation]\nedge_policy = \"stop\"\nscent_mode = \"cell\"\n\n[output]\nformat = \"json\"\n"
+	if err := ioutil.WriteFile(path, []byte(toml), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		want    func(c *Config)
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			want: func(c *Config) {},
+		},
+		{
+			name: "config file",
+			args: []string{"-config", path},
+			want: func(c *Config) {
+				c.Rules = domain.Rules{Edge: domain.EdgeStop, Scent: domain.ScentCell}
+				c.Format = domain.FormatJSON
+			},
+		},
+		{
+			name: "config file from the environment",
+			env:  map[string]string{"MARS_CONFIG": path},
+			want: func(c *Config) {
+				c.Rules = domain.Rules{Edge: domain.EdgeStop, Scent: domain.ScentCell}
+				c.Format = domain.FormatJSON
+			},
+		},
+		{
+			name: "environment overrides config file",
+			args: []string{"-config", path},
+			env:  map[string]string{"MARS_SIMULATION_SCENT_MODE": "off", "MARS_GRID_MAX_COORDINATE": "10"},
+			want: func(c *Config) {
+				c.MaxCoordinate = 10
+				c.Rules = domain.Rules{Edge: domain.EdgeStop, Scent: domain.ScentOff}
+				c.Format = domain.FormatJSON
+			},
+		},
+		{
+			name: "flags override environment",
+			args: []string{"-config", path, "-scent-mode", "pose", "-format", "text"},
+			env:  map[string]string{"MARS_SIMULATION_SCENT_MODE": "off"},
+			want: func(c *Config) {
+				c.Rules = domain.Rules{Edge: domain.EdgeStop, Scent: domain.ScentPose}
+			},
+		},
+		{
+			name:    "missing config file",
+			args:    []string{"-config", filepath.Join(dir, "missing.toml")},
+			wantErr: `unable to read config from path`,
+		},
+		{
+			name:    "invalid environment variable",
+			env:     map[string]string{"MARS_INSTRUCTIONS_MAX_LENGTH": "many"},
+			wantErr: `invalid MARS_INSTRUCTIONS_MAX_LENGTH "many"`,
+		},
+		{
+			name:    "invalid flag",
+			args:    []string{"-max-coordinate", "big"},
+			wantErr: `invalid -max-coordinate "big"`,
+		},
+		{
+			name:    "invalid setting",
+			args:    []string{"-log-format", "xml"},
+			wantErr: "log.format: unsupported log format xml, expected json or text",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			flags := NewConfigFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := flags.Config(func(key string) (string, bool) {
+				v, ok := tt.env[key]
+				return v, ok
+			})
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("Config() got error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Config() got error %v", err)
+			}
+
+			want := DefaultConfig()
+			tt.want(&want)
+			if got != want {
+				t.Errorf("Config() got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestConfig_TOML(t *testing.T) {
+	c := DefaultConfig()
+	c.Rules.Edge = domain.EdgeStop
+	c.MaxInstructions = 250
+
+	var got Config
+	if err := got.readTOML(strings.NewReader(c.TOML())); err != nil {
+		t.Fatalf("readTOML() got error %v reading back:\n%s", err, c.TOML())
+	}
+	if got != c {
+		t.Errorf("TOML() read back %+v, want %+v", got, c)
+	}
+}
+
+func TestConfigCommand(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := ConfigCommand([]string{"print", "-edge-policy", "stop"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("ConfigCommand() got code %d, stderr %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), `edge_policy = "stop" # MARS_SIMULATION_EDGE_POLICY`) {
+		t.Errorf("ConfigCommand() got output:\n%s", stdout.String())
+	}
+
+	if code := ConfigCommand(nil, ioutil.Discard, ioutil.Discard); code != 2 {
+		t.Errorf("ConfigCommand() without print got code %d, want 2", code)
+	}
+	if code := ConfigCommand([]string{"print", "-scent-mode", "sometimes"}, ioutil.Discard, ioutil.Discard); code != 1 {
+		t.Errorf("ConfigCommand() with an invalid setting got code %d, want 1", code)
+	}
+}
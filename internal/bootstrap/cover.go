@@ -0,0 +1,104 @@
+package bootstrap
+
+import (
+	"flag"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// stringsFlag is a repeatable string flag
+type stringsFlag []string
+
+// String returns the flag values
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ", ")
+}
+
+// Set appends a value to the flag
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// Cover plans instructions for a fleet of robots to visit every grid point of a surface
+// and writes them as a mission file (stdout or -output) followed by a coverage report (stderr)
+// the surface either comes from -surface or from a mission (-input-path) which is explored first
+// so its terrain and scents are taken into account
+func Cover(args []string, stdout, stderr io.Writer) int {
+	var robotLines stringsFlag
+	fs := flag.NewFlagSet("cover", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("input-path", "", "mission to explore before planning, its terrain and scents being avoided")
+	surface := fs.String("surface", "", `surface upper-right coordinates when no mission is given, ie: "5 3"`)
+	output := fs.String("output", "", "path of the mission file to write, standard output by default")
+	fs.Var(&robotLines, "robot", `start position line of a robot, can be repeated, ie: "1 1 E type=hover"`)
+	config := NewConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	c, err := config.Config(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	logger := c.logger(stderr)
+	builder := c.builder(logger)
+
+	if len(robotLines) == 0 {
+		fmt.Fprintln(stderr, "expected at least one -robot")
+		return 2
+	}
+	robots, err := builder.LoadRobotInstructions(robotLines)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid robot: %s\n", err)
+		return 2
+	}
+
+	planner := &domain.Planner{}
+	if *path != "" {
+		me, err := load(*path, c, logger)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		// the mission is only explored for its scents, its events aren't logged
+		me.SetLogger(nil)
+		me.SendInstructions()
+
+		planner.Surface = me.Surface
+		planner.Scents = me.Scents
+	} else {
+		planner.Surface, err = builder.NewSurface(*surface)
+		if err != nil {
+			fmt.Fprintf(stderr, "invalid surface: %s\n", err)
+			return 2
+		}
+	}
+
+	coverage, err := planner.Cover(robots, c.MaxInstructions)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	mission := &domain.MarsExplorer{Surface: planner.Surface, Robots: coverage.Robots}
+	content := strings.Join(mission.Mission(), "\n")
+	if *output == "" {
+		fmt.Fprint(stdout, content)
+	} else if err := ioutil.WriteFile(*output, []byte(content), 0644); err != nil {
+		fmt.Fprintf(stderr, "failed to write mission %s, got %q\n", *output, err)
+		return 1
+	}
+
+	fmt.Fprintf(stderr, "coverage: %d/%d grid points (%.1f%%)\n", len(coverage.Visited), coverage.Cells, coverage.Percent())
+	for i, r := range coverage.Robots {
+		fmt.Fprintf(stderr, "robot %s: %d instructions\n", r.Label(i), len(r.Instructions))
+	}
+
+	return 0
+}
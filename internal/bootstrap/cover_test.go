@@ -0,0 +1,70 @@
+package bootstrap
+
+import (
+	"bytes"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestCover(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantReport string
+		wantCode   int
+	}{
+		{
+			name:       "fleet covers a given surface",
+			args:       []string{"-surface", "5 3", "-robot", "0 0 N", "-robot", "5 3 S type=hover"},
+			wantReport: "coverage: 24/24 grid points (100.0%)",
+		},
+		{
+			name:       "fleet covers a surface after exploring a mission",
+			args:       []string{"-input-path", "../../test/golden/sample-1.input", "-robot", "0 0 N id=scout-1"},
+			wantReport: "coverage: 24/24 grid points (100.0%)",
+		},
+		{
+			name:     "missing robots",
+			args:     []string{"-surface", "5 3"},
+			wantCode: 2,
+		},
+		{
+			name:     "invalid robot",
+			args:     []string{"-surface", "5 3", "-robot", "0 0 N type=legged"},
+			wantCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := Cover(tt.args, &stdout, &stderr)
+			if code != tt.wantCode {
+				t.Fatalf("Cover() got code %d, want %d", code, tt.wantCode)
+			}
+			if code != 0 {
+				return
+			}
+			if !strings.HasPrefix(stderr.String(), tt.wantReport) {
+				t.Errorf("Cover() got report %q, want %q", stderr.String(), tt.wantReport)
+			}
+
+			// the output is a mission ready to be explored
+			l := logrus.New()
+			l.SetOutput(ioutil.Discard)
+			mb := domain.NewMarsBuilder(domain.NewLogrusLogger(l))
+			me, err := mb.Build(strings.Split(stdout.String(), "\n"))
+			if err != nil {
+				t.Fatalf("Cover() got an invalid mission, %v", err)
+			}
+			me.SendInstructions()
+			for _, r := range me.Robots {
+				if r.Lost {
+					t.Errorf("Cover() robot got lost, %s", r.ToString())
+				}
+			}
+		})
+	}
+}
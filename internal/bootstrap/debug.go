@@ -0,0 +1,189 @@
+package bootstrap
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// stdin is where the interactive commands read their input from
+var stdin io.Reader = os.Stdin
+
+const debugHelp = `commands:
+  step [n]                 execute the next n instructions (default 1)
+  back [n]                 step back n instructions (default 1)
+  continue                 execute until a breakpoint or the end of the mission
+  break robot <label>      stop on every instruction of a robot, ie: break robot #2
+  break cell <x> <y>       stop when a robot reaches a grid point
+  break event <name>       stop when an event is sent: moved, blocked, lost or scent-left
+  breakpoints              list the breakpoints
+  clear                    remove every breakpoint
+  show                     print the robots and scents
+  help                     print this help
+  quit                     leave the debugger
+`
+
+// Debug runs a mission one instruction at a time driven by commands typed on stdin
+func Debug(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("debug", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("input-path", "", "mission to debug")
+	config := NewConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	c, err := config.Config(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	me, err := load(*path, c, c.logger(stderr))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	// every step being printed, the simulation events aren't logged
+	me.SetLogger(nil)
+	d := domain.NewDebugger(me)
+	scanner := bufio.NewScanner(stdin)
+	for {
+		fmt.Fprint(stdout, "(debug) ")
+		if !scanner.Scan() {
+			fmt.Fprintln(stdout)
+			return 0
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "quit" || fields[0] == "q" {
+			return 0
+		}
+		if err := debugCommand(d, fields, stdout); err != nil {
+			fmt.Fprintln(stdout, err)
+		}
+	}
+}
+
+// debugCommand executes a single debugger command
+func debugCommand(d *domain.Debugger, fields []string, out io.Writer) error {
+	switch fields[0] {
+	case "step", "s":
+		n, err := count(fields)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			s, ok := d.Step()
+			if !ok {
+				fmt.Fprintln(out, "mission complete")
+				return nil
+			}
+			printStep(s, out)
+		}
+	case "back", "b":
+		n, err := count(fields)
+		if err != nil {
+			return err
+		}
+		undone := d.Back(n)
+		fmt.Fprintf(out, "stepped back %d instructions, %d executed\n", undone, len(d.Steps()))
+	case "continue", "c":
+		s, hit := d.Continue()
+		if s != nil {
+			printStep(s, out)
+		}
+		if hit != nil {
+			fmt.Fprintf(out, "breakpoint hit: %s\n", hit.String())
+		} else {
+			fmt.Fprintln(out, "mission complete")
+		}
+	case "break":
+		b, err := parseBreakpoint(fields[1:])
+		if err != nil {
+			return err
+		}
+		d.Breakpoints = append(d.Breakpoints, b)
+		fmt.Fprintf(out, "breakpoint %d: %s\n", len(d.Breakpoints), b.String())
+	case "breakpoints":
+		for i, b := range d.Breakpoints {
+			fmt.Fprintf(out, "breakpoint %d: %s\n", i+1, b.String())
+		}
+	case "clear":
+		d.Breakpoints = nil
+	case "show":
+		me := d.Explorer()
+		for i := range me.Robots {
+			fmt.Fprintf(out, "robot %s: %s\n", me.Robots[i].Label(i), me.Robots[i].ToString())
+		}
+		for _, s := range me.Scents {
+			fmt.Fprintf(out, "scent: %d %d %s\n", s.PosX, s.PosY, s.Direction)
+		}
+	case "help", "h":
+		fmt.Fprint(out, debugHelp)
+	default:
+		return fmt.Errorf("unknown command %q, type help for the list of commands", fields[0])
+	}
+
+	return nil
+}
+
+// count reads the optional instruction count of a step or back command
+func count(fields []string) (int, error) {
+	if len(fields) < 2 {
+		return 1, nil
+	}
+
+	n, err := strconv.Atoi(fields[1])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid count %q, expected a positive number", fields[1])
+	}
+
+	return n, nil
+}
+
+// parseBreakpoint reads the arguments of a break command
+func parseBreakpoint(args []string) (domain.Breakpoint, error) {
+	switch {
+	case len(args) == 2 && args[0] == "robot":
+		return domain.Breakpoint{Robot: args[1]}, nil
+	case len(args) == 3 && args[0] == "cell":
+		x, errX := strconv.Atoi(args[1])
+		y, errY := strconv.Atoi(args[2])
+		if errX != nil || errY != nil {
+			return domain.Breakpoint{}, fmt.Errorf("invalid cell %s %s", args[1], args[2])
+		}
+		return domain.Breakpoint{Cell: &domain.Point{X: x, Y: y}}, nil
+	case len(args) == 2 && args[0] == "event":
+		switch args[1] {
+		case domain.EventMoved, domain.EventBlocked, domain.EventLost, domain.EventScentLeft:
+			return domain.Breakpoint{Event: args[1]}, nil
+		}
+		return domain.Breakpoint{}, fmt.Errorf("unknown event %q", args[1])
+	default:
+		return domain.Breakpoint{}, fmt.Errorf("usage: break robot <label> | break cell <x> <y> | break event <name>")
+	}
+}
+
+// printStep writes a step followed by the events it sent
+func printStep(s *domain.Step, out io.Writer) {
+	fmt.Fprintln(out, s.String())
+	for _, e := range s.Events {
+		switch e := e.(type) {
+		case domain.BlockedEvent:
+			fmt.Fprintf(out, "  blocked by %s at %d %d %s\n", e.By, e.Pose.X, e.Pose.Y, e.Pose.Direction)
+		case domain.LostEvent:
+			fmt.Fprintf(out, "  lost (%s) at %d %d %s\n", e.Loss.Cause, e.Pose.X, e.Pose.Y, e.Pose.Direction)
+		case domain.ScentLeftEvent:
+			fmt.Fprintf(out, "  scent left at %d %d %s\n", e.Pose.X, e.Pose.Y, e.Pose.Direction)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package bootstrap
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestDebug(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		want     []string
+		wantCode int
+	}{
+		{
+			name:  "step and back",
+			args:  []string{"-input-path", "../../test/golden/sample-1.input"},
+			input: "step 2\nback\nshow\nquit\n",
+			want: []string{
+				"step 1: robot #1 instruction 0 R: 1 1 E -> 1 1 S",
+				"step 2: robot #1 instruction 1 F: 1 1 S -> 1 0 S",
+				"stepped back 1 instructions, 1 executed",
+				"robot #1: 1 1 S",
+			},
+		},
+		{
+			name:  "continue up to a breakpoint",
+			args:  []string{"-input-path", "../../test/golden/sample-1.input"},
+			input: "break event lost\ncontinue\nshow\n",
+			want: []string{
+				"breakpoint 1: event lost",
+				"step 16: robot #2 instruction 7 F: 3 3 N -> 3 3 N",
+				"  lost (edge) at 3 3 N",
+				"  scent left at 3 3 N",
+				"breakpoint hit: event lost",
+				"robot #2: 3 3 N LOST",
+				"scent: 3 3 N",
+			},
+		},
+		{
+			name:  "invalid commands are reported",
+			args:  []string{"-input-path", "../../test/golden/sample-1.input"},
+			input: "jump\nbreak event crash\nstep -1\n",
+			want: []string{
+				`unknown command "jump"`,
+				`unknown event "crash"`,
+				`invalid count "-1"`,
+			},
+		},
+		{
+			name:     "missing mission",
+			args:     []string{"-input-path", "./missing.txt"},
+			wantCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(r io.Reader) { stdin = r }(stdin)
+			stdin = strings.NewReader(tt.input)
+
+			var stdout bytes.Buffer
+			code := Debug(tt.args, &stdout, ioutil.Discard)
+			if code != tt.wantCode {
+				t.Errorf("Debug() got code %d, want %d", code, tt.wantCode)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(stdout.String(), w) {
+					t.Errorf("Debug() got %q, want it to contain %q", stdout.String(), w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package bootstrap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around changes
+const diffContext = 3
+
+// diffLine is a line of a diff, kind being ' ' when unchanged, '-' when removed and '+' when added
+type diffLine struct {
+	kind byte
+	text string
+}
+
+// unifiedDiff returns the line differences between two texts as a unified diff, empty when they are the same
+func unifiedDiff(fromName, toName, from, to string) string {
+	if from == to {
+		return ""
+	}
+
+	a, b := splitLines(from), splitLines(to)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+
+	// line numbers of both texts at the start of every diff line
+	fromLine, toLine := make([]int, len(lines)+1), make([]int, len(lines)+1)
+	for k, l := range lines {
+		fromLine[k+1], toLine[k+1] = fromLine[k], toLine[k]
+		if l.kind != '+' {
+			fromLine[k+1]++
+		}
+		if l.kind != '-' {
+			toLine[k+1]++
+		}
+	}
+
+	for start := 0; start < len(lines); {
+		if lines[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		// a hunk goes on as long as changes are less than twice the context apart
+		end := start
+		for k := start; k < len(lines) && k <= end+2*diffContext; k++ {
+			if lines[k].kind != ' ' {
+				end = k
+			}
+		}
+		first, last := max(start-diffContext, 0), min(end+diffContext, len(lines)-1)
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(fromLine[first], fromLine[last+1]-fromLine[first]), hunkRange(toLine[first], toLine[last+1]-toLine[first]))
+		for _, l := range lines[first : last+1] {
+			fmt.Fprintf(&sb, "%c%s\n", l.kind, l.text)
+		}
+
+		start = last + 1
+	}
+
+	return sb.String()
+}
+
+// hunkRange returns the range of a hunk, line numbers starting at 1, ie: "3,4"
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits a text into lines, a trailing new line not making an extra empty line
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,43 @@
+package bootstrap
+
+import "testing"
+
+func Test_unifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			name: "same texts",
+			from: "1 1 E\n",
+			to:   "1 1 E\n",
+		},
+		{
+			name: "changed line",
+			from: "1 1 E\n3 3 N LOST\n2 3 S\n",
+			to:   "1 1 E\n3 3 N\n2 3 S\n",
+			want: "--- want\n+++ got\n@@ -1,3 +1,3 @@\n 1 1 E\n-3 3 N LOST\n+3 3 N\n 2 3 S\n",
+		},
+		{
+			name: "added lines",
+			from: "",
+			to:   "1 1 E\n",
+			want: "--- want\n+++ got\n@@ -0,0 +1,1 @@\n+1 1 E\n",
+		},
+		{
+			name: "changes far apart make separate hunks",
+			from: "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n",
+			to:   "A\nb\nc\nd\ne\nf\ng\nh\ni\nJ\n",
+			want: "--- want\n+++ got\n@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n d\n@@ -7,4 +7,4 @@\n g\n h\n i\n-j\n+J\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("want", "got", tt.from, tt.to); got != tt.want {
+				t.Errorf("unifiedDiff() got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package bootstrap
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Fmt writes missions in the canonical layout (see domain.MarsBuilder.Format): fmt [-check | -w] path...
+// formatted missions are written to stdout, -w rewriting the files instead and -check listing the files which
+// aren't canonical, exiting with 1 when there is any
+// it exits with 2 when a mission can't be read
+func Fmt(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	check := fs.Bool("check", false, "list the missions which aren't canonical, exiting with 1 when there is any")
+	write := fs.Bool("w", false, "rewrite the missions which aren't canonical, listing them")
+	config := NewConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "expected mission paths, ie: fmt -check ./missions/*.txt")
+		return 2
+	}
+	if *check && *write {
+		fmt.Fprintln(stderr, "-check and -w can't be combined")
+		return 2
+	}
+	c, err := config.Config(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	// the mission errors are reported along with their path, they aren't logged
+	builder := c.builder(newLogger(ioutil.Discard))
+	code := 0
+	for _, path := range fs.Args() {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "unable to read instructions from path \"%s\" - got %q\n", path, err)
+			code = 2
+			continue
+		}
+
+		lines, err := builder.Format(strings.Split(string(data), "\n"))
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %s\n", path, err)
+			code = 2
+			continue
+		}
+		formatted := strings.Join(lines, "\n") + "\n"
+
+		switch {
+		case *check:
+			if formatted != string(data) {
+				fmt.Fprintln(stdout, path)
+				if code == 0 {
+					code = 1
+				}
+			}
+		case *write:
+			if formatted == string(data) {
+				continue
+			}
+			if err := ioutil.WriteFile(path, []byte(formatted), 0644); err != nil {
+				fmt.Fprintf(stderr, "unable to write mission to path \"%s\" - got %q\n", path, err)
+				code = 2
+				continue
+			}
+			fmt.Fprintln(stdout, path)
+		default:
+			fmt.Fprint(stdout, formatted)
+		}
+	}
+
+	return code
+}
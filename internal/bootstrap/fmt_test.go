@@ -0,0 +1,90 @@
+package bootstrap
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFmt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fmt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const canonical = "# scout\n5 3\n1 1 E\nRF\n"
+	files := map[string]string{
+		"canonical.txt": canonical,
+		"messy.txt":     "# scout\r\n5 3 \r\n\r\n1 1 e\r\nrf\r\n\r\n",
+		"broken.txt":    "5 3\n1 1 E\nRXF\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path := func(name string) string { return filepath.Join(dir, name) }
+
+	tests := []struct {
+		name     string
+		args     []string
+		want     string
+		wantCode int
+	}{
+		{
+			name: "print",
+			args: []string{path("messy.txt")},
+			want: canonical,
+		},
+		{
+			name: "check canonical",
+			args: []string{"-check", path("canonical.txt")},
+		},
+		{
+			name:     "check not canonical",
+			args:     []string{"-check", path("canonical.txt"), path("messy.txt")},
+			want:     path("messy.txt") + "\n",
+			wantCode: 1,
+		},
+		{
+			name:     "check invalid mission",
+			args:     []string{"-check", path("messy.txt"), path("broken.txt")},
+			want:     path("messy.txt") + "\n",
+			wantCode: 2,
+		},
+		{
+			name:     "no path",
+			args:     []string{"-check"},
+			wantCode: 2,
+		},
+		{
+			name:     "check and write",
+			args:     []string{"-check", "-w", path("messy.txt")},
+			wantCode: 2,
+		},
+		{
+			name: "write",
+			args: []string{"-w", path("canonical.txt"), path("messy.txt")},
+			want: path("messy.txt") + "\n",
+		},
+		{
+			name: "check once written",
+			args: []string{"-check", path("messy.txt")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			code := Fmt(tt.args, &stdout, ioutil.Discard)
+			if code != tt.wantCode {
+				t.Errorf("Fmt() got code %d, want %d", code, tt.wantCode)
+			}
+			if stdout.String() != tt.want {
+				t.Errorf("Fmt() got %q, want %q", stdout.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package bootstrap
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// Lint prints the suspicious parts of a mission along with a suggested fix, see domain.MarsBuilder.Lint
+// it exits with 1 when there is any warning and 2 when the mission can't be read
+func Lint(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("input-path", "", "mission to lint")
+	config := NewConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	c, err := config.Config(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	lines, err := NewFileInstructions(*path)
+	if err != nil {
+		fmt.Fprintf(stderr, "unable to read instructions from path \"%s\" - got %q\n", *path, err)
+		return 2
+	}
+
+	// the mission errors are reported as the lint result, they aren't logged
+	builder := c.builder(newLogger(ioutil.Discard))
+	warnings, err := builder.Lint(lines)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %s\n", *path, err)
+		return 2
+	}
+
+	for _, w := range warnings {
+		fmt.Fprintf(stdout, "%s: %s\n", *path, w.String())
+	}
+
+	if len(warnings) > 0 {
+		return 1
+	}
+
+	return 0
+}
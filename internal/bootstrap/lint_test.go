@@ -0,0 +1,54 @@
+package bootstrap
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	clean, err := ioutil.TempFile("", "mission")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(clean.Name())
+	_, _ = clean.WriteString("5 3\n1 1 E\nRFRFRFRF\n")
+	clean.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		want     string
+		wantCode int
+	}{
+		{
+			name: "mission with warnings",
+			args: []string{"-input-path", "../../test/golden/sample-1.input"},
+			want: "../../test/golden/sample-1.input: line 6: W002 robot #2: lost (edge) at instruction 7, " +
+				"the 5 instructions after it are never run (fix: remove RRFLL)\n",
+			wantCode: 1,
+		},
+		{
+			name: "mission without warnings",
+			args: []string{"-input-path", clean.Name()},
+		},
+		{
+			name:     "missing mission",
+			args:     []string{"-input-path", "./missing.txt"},
+			wantCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			code := Lint(tt.args, &stdout, ioutil.Discard)
+			if code != tt.wantCode {
+				t.Errorf("Lint() got code %d, want %d", code, tt.wantCode)
+			}
+			if stdout.String() != tt.want {
+				t.Errorf("Lint() got %q, want %q", stdout.String(), tt.want)
+			}
+		})
+	}
+}
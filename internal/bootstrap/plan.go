@@ -0,0 +1,82 @@
+package bootstrap
+
+import (
+	"flag"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"io"
+	"os"
+	"strings"
+)
+
+// Plan prints the shortest instructions driving a robot from a pose to another
+// the surface either comes from -surface or from a mission (-input-path) which is explored first
+// so its terrain, scents and remaining robots are taken into account
+func Plan(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("input-path", "", "mission to explore before planning, its scents and robots being avoided")
+	surface := fs.String("surface", "", `surface upper-right coordinates when no mission is given, ie: "5 3"`)
+	from := fs.String("from", "", `start pose, ie: "1 1 E"`)
+	to := fs.String("to", "", `goal pose, ie: "3 2 N"`)
+	robotType := fs.String("type", "", "robot type to plan for, wheeled by default")
+	config := NewConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	c, err := config.Config(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	logger := c.logger(stderr)
+
+	start, err := domain.ParsePose(*from)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid start pose: %s\n", err)
+		return 2
+	}
+	goal, err := domain.ParsePose(*to)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid goal pose: %s\n", err)
+		return 2
+	}
+
+	planner := &domain.Planner{Type: *robotType, Obstacles: map[domain.Point]bool{}}
+	if *path != "" {
+		me, err := load(*path, c, logger)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		// the mission is only explored for its scents, its events aren't logged
+		me.SetLogger(nil)
+		me.SendInstructions()
+
+		planner.Surface = me.Surface
+		planner.Scents = me.Scents
+		for _, r := range me.Robots {
+			if !r.Lost {
+				planner.Obstacles[domain.Point{X: r.PosX, Y: r.PosY}] = true
+			}
+		}
+	} else {
+		builder := c.builder(logger)
+		planner.Surface, err = builder.NewSurface(*surface)
+		if err != nil {
+			fmt.Fprintf(stderr, "invalid surface: %s\n", err)
+			return 2
+		}
+	}
+
+	instructions, err := planner.Plan(start, goal)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	fmt.Fprintln(stdout, strings.Join(instructions, ""))
+
+	return 0
+}
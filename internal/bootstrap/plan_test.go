@@ -0,0 +1,49 @@
+package bootstrap
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     string
+		wantCode int
+	}{
+		{
+			name: "plan on a given surface",
+			args: []string{"-surface", "5 3", "-from", "1 1 E", "-to", "3 3 N"},
+			want: "FFLFF\n",
+		},
+		{
+			name: "plan after exploring a mission",
+			args: []string{"-input-path", "../../test/golden/sample-1.input", "-from", "3 3 S", "-to", "3 3 N"},
+			want: "LL\n",
+		},
+		{
+			name:     "goal occupied by a robot of the mission",
+			args:     []string{"-input-path", "../../test/golden/sample-1.input", "-from", "0 0 N", "-to", "2 3 S"},
+			wantCode: 1,
+		},
+		{
+			name:     "invalid pose",
+			args:     []string{"-surface", "5 3", "-from", "1 1", "-to", "3 3 N"},
+			wantCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			code := Plan(tt.args, &stdout, ioutil.Discard)
+			if code != tt.wantCode {
+				t.Errorf("Plan() got code %d, want %d", code, tt.wantCode)
+			}
+			if stdout.String() != tt.want {
+				t.Errorf("Plan() got %q, want %q", stdout.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package bootstrap
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const replHelp = `commands:
+  new <x> <y>              create an empty surface
+  load <path>              load a mission file, exploring it
+  deploy <x> <y> <d> [...] deploy a robot and pilot it, options as in a mission file, ie: deploy 1 1 E type=hover
+  use <label>              pilot another robot, ie: use #2
+  <instructions>           execute instructions on the robot being piloted, ie: FRF[O?L:F]
+  undo                     undo the last command changing the mission
+  show                     print the robots and scents
+  show map                 print the surface, see README for the legend
+  save <path>              write the mission as a mission file
+  help                     print this help
+  quit                     leave the repl
+`
+
+// repl is the state of an interactive session
+// mission is what was typed, robots being at their deployed pose along with every instruction they were given,
+// explorer being the mission explored so far
+type repl struct {
+	builder         domain.MarsBuilder
+	rules           domain.Rules
+	maxInstructions int
+	mission         *domain.MarsExplorer
+	explorer        *domain.MarsExplorer
+	piloted         int
+	warnings        *warningRecorder
+	history         []replState
+	out             io.Writer
+}
+
+// replState is what undo goes back to
+type replState struct {
+	mission  *domain.MarsExplorer
+	explorer *domain.MarsExplorer
+	piloted  int
+}
+
+// warningRecorder records forward moves ignored and robots lost as warnings
+type warningRecorder struct {
+	domain.NopObserver
+	explorer *domain.MarsExplorer
+	warnings []string
+}
+
+// Blocked records a forward move ignored
+func (w *warningRecorder) Blocked(e domain.BlockedEvent) {
+	w.warnings = append(w.warnings, fmt.Sprintf("robot %s: instruction %d: forward move ignored because of a %s at %d %d %s",
+		w.explorer.Robots[e.Robot].Label(e.Robot), e.Instruction, e.By, e.Pose.X, e.Pose.Y, e.Pose.Direction))
+}
+
+// Lost records a robot not operating anymore
+func (w *warningRecorder) Lost(e domain.LostEvent) {
+	w.warnings = append(w.warnings, fmt.Sprintf("robot %s: instruction %d: lost (%s) reaching %d %d",
+		w.explorer.Robots[e.Robot].Label(e.Robot), e.Loss.Instruction, e.Loss.Cause, e.Loss.Target.X, e.Loss.Target.Y))
+}
+
+// Repl pilots robots interactively, executing commands typed on stdin one at a time
+func Repl(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("repl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("input-path", "", "mission to start from")
+	surface := fs.String("surface", "", `surface to start from, ie: "5 3"`)
+	config := NewConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	c, err := config.Config(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	r := &repl{
+		builder: c.builder(c.logger(stderr)), rules: c.Rules, maxInstructions: c.MaxInstructions,
+		warnings: &warningRecorder{}, out: stdout,
+	}
+	switch {
+	case *path != "":
+		if err := r.load(*path); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		r.status()
+	case *surface != "":
+		if err := r.create(*surface); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+
+	scanner := bufio.NewScanner(stdin)
+	for {
+		fmt.Fprint(stdout, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(stdout)
+			return 0
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "quit" || fields[0] == "q" {
+			return 0
+		}
+		if err := r.command(fields); err != nil {
+			fmt.Fprintln(stdout, err)
+		}
+	}
+}
+
+// command executes a single repl command
+func (r *repl) command(fields []string) error {
+	switch fields[0] {
+	case "new":
+		if len(fields) != 3 {
+			return fmt.Errorf("usage: new <x> <y>")
+		}
+		r.save()
+		if err := r.create(strings.Join(fields[1:], " ")); err != nil {
+			r.undo()
+			return err
+		}
+		fmt.Fprintf(r.out, "surface %s %s\n", fields[1], fields[2])
+	case "load":
+		if len(fields) != 2 {
+			return fmt.Errorf("usage: load <path>")
+		}
+		r.save()
+		if err := r.load(fields[1]); err != nil {
+			r.undo()
+			return err
+		}
+		r.status()
+	case "deploy":
+		if err := r.ready(); err != nil {
+			return err
+		}
+		if len(fields) < 4 {
+			return fmt.Errorf("usage: deploy <x> <y> <d> [options]")
+		}
+		robots, err := r.builder.LoadRobotInstructions([]string{strings.Join(fields[1:], " ")})
+		if err != nil {
+			return fmt.Errorf("failed to deploy the robot, got %q", err)
+		}
+		r.save()
+		ri, err := r.mission.Deploy(robots[0].Copy())
+		if err == nil {
+			_, err = r.explorer.Deploy(robots[0])
+		}
+		if err != nil {
+			r.undo()
+			return err
+		}
+		r.piloted = ri
+		r.pose()
+	case "use":
+		if err := r.ready(); err != nil {
+			return err
+		}
+		if len(fields) != 2 {
+			return fmt.Errorf("usage: use <label>")
+		}
+		for i := range r.explorer.Robots {
+			if r.explorer.Robots[i].Label(i) == fields[1] {
+				r.piloted = i
+				r.pose()
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown robot %s", fields[1])
+	case "undo":
+		if !r.undo() {
+			return fmt.Errorf("nothing to undo")
+		}
+		if r.explorer != nil {
+			r.status()
+		}
+	case "show":
+		if err := r.ready(); err != nil {
+			return err
+		}
+		if len(fields) > 1 && fields[1] == "map" {
+			fmt.Fprintln(r.out, strings.Join(r.explorer.Map(), "\n"))
+			return nil
+		}
+		r.status()
+	case "save":
+		if err := r.ready(); err != nil {
+			return err
+		}
+		if len(fields) != 2 {
+			return fmt.Errorf("usage: save <path>")
+		}
+		if err := ioutil.WriteFile(fields[1], []byte(strings.Join(r.mission.Mission(), "\n")), 0644); err != nil {
+			return fmt.Errorf(`unable to write mission to path "%s" - got %q`, fields[1], err)
+		}
+		fmt.Fprintf(r.out, "mission saved to %s\n", fields[1])
+	case "help", "h":
+		fmt.Fprint(r.out, replHelp)
+	default:
+		if len(fields) != 1 || !strings.ContainsAny(fields[0][:1], "ABCDEFGHIJKLMNOPQRSTUVWXYZ[") {
+			return fmt.Errorf("unknown command %q, type help for the list of commands", fields[0])
+		}
+		return r.pilot(fields[0])
+	}
+
+	return nil
+}
+
+// pilot executes instructions on the robot being piloted
+func (r *repl) pilot(line string) error {
+	if err := r.ready(); err != nil {
+		return err
+	}
+	if r.piloted < 0 {
+		return fmt.Errorf("no robot to pilot, deploy one first")
+	}
+
+	r.save()
+	r.warnings.warnings = nil
+	if err := r.explorer.Pilot(r.piloted, line); err != nil {
+		r.undo()
+		return err
+	}
+	r.mission.Robots[r.piloted].Instructions = append([]string(nil), r.explorer.Robots[r.piloted].Instructions...)
+
+	r.pose()
+	for _, w := range r.warnings.warnings {
+		fmt.Fprintf(r.out, "warning: %s\n", w)
+	}
+	r.scents()
+
+	return nil
+}
+
+// create starts a new mission on an empty surface, ie: "5 3"
+func (r *repl) create(surface string) error {
+	s, err := r.builder.NewSurface(surface)
+	if err != nil {
+		return fmt.Errorf("failed to build mars surface, got %q", err)
+	}
+
+	r.mission = &domain.MarsExplorer{Surface: s, Rules: r.rules, MaxInstructions: r.maxInstructions}
+	r.explore(r.mission.Copy(), -1)
+
+	return nil
+}
+
+// load starts from a mission file, exploring it
+func (r *repl) load(path string) error {
+	me, err := build(path, r.builder)
+	if err != nil {
+		return err
+	}
+
+	r.mission = me
+	r.explore(me.Copy(), len(me.Robots)-1)
+	r.explorer.SendInstructions()
+
+	return nil
+}
+
+// explore sets the mission being explored, recording its warnings
+func (r *repl) explore(me *domain.MarsExplorer, piloted int) {
+	r.explorer = me
+	r.piloted = piloted
+	r.warnings.explorer = me
+	me.Observe(r.warnings)
+}
+
+// ready makes sure there is a surface to work with
+func (r *repl) ready() error {
+	if r.explorer == nil {
+		return fmt.Errorf("no surface, create one with new or load a mission first")
+	}
+
+	return nil
+}
+
+// save records the current state so that the next change can be undone
+func (r *repl) save() {
+	s := replState{piloted: r.piloted}
+	if r.mission != nil {
+		s.mission, s.explorer = r.mission.Copy(), r.explorer.Copy()
+	}
+	r.history = append(r.history, s)
+}
+
+// undo goes back to the last recorded state
+func (r *repl) undo() bool {
+	if len(r.history) == 0 {
+		return false
+	}
+
+	s := r.history[len(r.history)-1]
+	r.history = r.history[:len(r.history)-1]
+	r.mission = s.mission
+	if s.explorer == nil {
+		r.explorer = nil
+		r.piloted = s.piloted
+		return true
+	}
+	r.explore(s.explorer, s.piloted)
+
+	return true
+}
+
+// pose prints the robot being piloted
+func (r *repl) pose() {
+	if r.piloted < 0 {
+		return
+	}
+	fmt.Fprintf(r.out, "robot %s: %s\n", r.explorer.Robots[r.piloted].Label(r.piloted), r.explorer.Robots[r.piloted].ToString())
+}
+
+// scents prints the scents left so far
+func (r *repl) scents() {
+	for _, s := range r.explorer.Scents {
+		fmt.Fprintf(r.out, "scent: %d %d %s\n", s.PosX, s.PosY, s.Direction)
+	}
+}
+
+// status prints every robot and scent
+func (r *repl) status() {
+	for i := range r.explorer.Robots {
+		fmt.Fprintf(r.out, "robot %s: %s\n", r.explorer.Robots[i].Label(i), r.explorer.Robots[i].ToString())
+	}
+	r.scents()
+}
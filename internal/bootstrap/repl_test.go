@@ -0,0 +1,102 @@
+package bootstrap
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRepl(t *testing.T) {
+	dir, err := ioutil.TempDir("", "repl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	saved := filepath.Join(dir, "mission.txt")
+
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		want     []string
+		wantCode int
+	}{
+		{
+			name:  "pilot robots on a new surface",
+			input: "new 5 3\ndeploy 1 1 E\nRFRFRFRF\ndeploy 3 2 N id=scout\nFRRFLLFF\ndeploy 0 3 W\nLLFFFLFLFL\n",
+			want: []string{
+				"robot #1: 1 1 E",
+				"robot scout: 3 3 N LOST id=scout\nwarning: robot scout: instruction 7: lost (edge) reaching 3 4\nscent: 3 3 N",
+				"robot #3: 2 3 S\nwarning: robot #3: instruction 6: forward move ignored because of a scent at 3 3 N\nscent: 3 3 N",
+			},
+		},
+		{
+			name:  "undo",
+			args:  []string{"-surface", "5 3"},
+			input: "deploy 3 2 N\nFRRFLLFF\nundo\nshow\nshow map\n",
+			want: []string{
+				"> robot #1: 3 2 N\n> ......\n...^..\n......\n......",
+			},
+		},
+		{
+			name:  "load, pilot and save",
+			args:  []string{"-input-path", "../../test/golden/sample-1.input"},
+			input: "use #1\nFF\nsave " + saved + "\nload " + saved + "\n",
+			want: []string{
+				"robot #1: 1 1 E\nrobot #2: 3 3 N LOST\nrobot #3: 2 3 S\nscent: 3 3 N",
+				"robot #1: 3 1 E",
+				"mission saved to " + saved,
+				"robot #1: 3 1 E\nrobot #2: 3 3 N LOST\nrobot #3: 2 3 S\nscent: 3 3 N",
+			},
+		},
+		{
+			name:  "invalid commands are reported",
+			input: "deploy 1 1 E\nnew 5 3\nF\ndeploy 7 7 N\nfly\nundo\nundo\n",
+			want: []string{
+				"no surface, create one with new or load a mission first",
+				"no robot to pilot, deploy one first",
+				"robot can't be deployed off the grid at 7 7",
+				`unknown command "fly"`,
+				"nothing to undo",
+			},
+		},
+		{
+			name:  "configured instruction limit",
+			args:  []string{"-max-instructions", "5"},
+			input: "new 5 3\ndeploy 1 1 N\nRRRR\nLL\nR\n",
+			want:  []string{"> instructions are limited to 5\n> robot #1: 1 1 E"},
+		},
+		{
+			name:  "instruction limit above the default one",
+			args:  []string{"-max-instructions", "200"},
+			input: "new 5 3\ndeploy 1 1 N\n" + strings.Repeat("RL", 60) + "\n",
+			want:  []string{"robot #1: 1 1 N"},
+		},
+		{
+			name:     "missing mission",
+			args:     []string{"-input-path", "./missing.txt"},
+			wantCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(r io.Reader) { stdin = r }(stdin)
+			stdin = strings.NewReader(tt.input)
+
+			var stdout bytes.Buffer
+			code := Repl(tt.args, &stdout, ioutil.Discard)
+			if code != tt.wantCode {
+				t.Errorf("Repl() got code %d, want %d", code, tt.wantCode)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(stdout.String(), w) {
+					t.Errorf("Repl() got %q, want it to contain %q", stdout.String(), w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package bootstrap
+
+import (
+	"flag"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"io"
+	"os"
+)
+
+// RunMissions explores independent missions on a pool of workers, see Runner
+// reports are written to stdout in the order of the paths, errors and throughput stats to stderr
+// it exits with 1 when any mission failed
+func RunMissions(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var r Runner
+	fs.IntVar(&r.Workers, "workers", 0, "number of missions explored at once (GOMAXPROCS by default)")
+	fs.IntVar(&r.MaxSteps, "max-steps", 0, "stop every mission once as many instructions were executed (no limit by default)")
+	metrics := fs.String("metrics", "", `path of a JSON file to dump the missions metrics to once done, "-" for standard error`)
+	timeout := fs.Duration("timeout", 0, "stop once elapsed, missions not done failing (no timeout by default)")
+	config := NewConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, "expected mission paths, ie: run ./missions/*.txt")
+		return 2
+	}
+	var err error
+	if r.Config, err = config.Config(os.LookupEnv); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	if *metrics != "" {
+		r.Metrics = domain.NewMetrics(nil)
+	}
+
+	ctx, cancel := interruptible(*timeout)
+	defer cancel()
+
+	stats, err := r.Run(ctx, fs.Args(), stdout)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+	}
+	fmt.Fprintln(stderr, stats.String())
+	if r.Metrics != nil {
+		if err := dumpMetrics(r.Metrics, *metrics, stderr); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+
+	if err != nil {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,179 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"io"
+	"io/ioutil"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Runner explores independent missions on a bounded pool of workers
+type Runner struct {
+	// Workers is the number of missions explored at once, GOMAXPROCS when 0
+	Workers int
+	// Config holds the limits, policies and report output format of every mission, the defaults when zero
+	Config Config
+	// MaxSteps stops every mission once as many instructions were executed, 0 meaning no limit
+	MaxSteps int
+	// Metrics collects the statistics of every mission explored, when set
+	Metrics *domain.Metrics
+}
+
+// RunError is the error of a mission run by a Runner
+type RunError struct {
+	Path string
+	Err  error
+}
+
+func (e RunError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Err)
+}
+
+// RunErrors aggregates the errors of the missions run by a Runner, in the missions order
+type RunErrors []RunError
+
+func (e RunErrors) Error() string {
+	lines := make([]string, 0, len(e))
+	for _, err := range e {
+		lines = append(lines, err.Error())
+	}
+
+	return fmt.Sprintf("missions failed (%d):\n%s", len(e), strings.Join(lines, "\n"))
+}
+
+// RunStats is the throughput of a Runner
+type RunStats struct {
+	Missions int
+	Failed   int
+	Robots   int
+	Steps    int
+	Elapsed  time.Duration
+}
+
+// String returns the stats as a single line
+func (s RunStats) String() string {
+	seconds := s.Elapsed.Seconds()
+	if seconds == 0 {
+		seconds = time.Nanosecond.Seconds()
+	}
+
+	return fmt.Sprintf("%d missions (%d failed), %d robots, %d steps in %s: %.0f missions/s, %.0f steps/s",
+		s.Missions, s.Failed, s.Robots, s.Steps, s.Elapsed, float64(s.Missions)/seconds, float64(s.Steps)/seconds)
+}
+
+// runResult is the outcome of a single mission
+type runResult struct {
+	output []byte
+	robots int
+	steps  int
+	err    error
+}
+
+// Run explores the missions of the given paths, writing their reports to out in the order of paths,
+// each one as soon as every mission before it is done
+// a failing mission doesn't stop the others, the returned error being RunErrors when any failed
+// missions not started yet once the context is done fail with the context error
+func (r Runner) Run(ctx context.Context, paths []string, out io.Writer) (RunStats, error) {
+	start := time.Now()
+	results := make([]chan runResult, len(paths))
+	for i := range results {
+		results[i] = make(chan runResult, 1)
+	}
+
+	go forEach(len(paths), r.Workers, func(i int) {
+		results[i] <- r.run(ctx, paths[i])
+	})
+
+	stats := RunStats{Missions: len(paths)}
+	var errs RunErrors
+	for i, path := range paths {
+		res := <-results[i]
+		stats.Robots += res.robots
+		stats.Steps += res.steps
+		if res.err != nil {
+			stats.Failed++
+			errs = append(errs, RunError{Path: path, Err: res.err})
+			continue
+		}
+
+		fmt.Fprintf(out, "== %s\n", path)
+		_, _ = out.Write(res.output)
+	}
+	stats.Elapsed = time.Since(start)
+
+	if len(errs) > 0 {
+		return stats, errs
+	}
+
+	return stats, nil
+}
+
+// run explores a single mission
+func (r Runner) run(ctx context.Context, path string) runResult {
+	if err := ctx.Err(); err != nil {
+		return runResult{err: err}
+	}
+
+	me, err := load(path, r.Config, newLogger(ioutil.Discard))
+	if err != nil {
+		return runResult{err: err}
+	}
+
+	res := runResult{robots: len(me.Robots)}
+	var progress *domain.Progress
+	explore := func() { progress, err = me.SendInstructionsContext(ctx, r.MaxSteps) }
+	if r.Metrics != nil {
+		r.Metrics.Explore(me, explore)
+	} else {
+		explore()
+	}
+	res.steps = progress.Steps
+	if err != nil {
+		res.err = fmt.Errorf("exploration interrupted after %d steps, %q", progress.Steps, err)
+		return res
+	}
+
+	reporter, err := domain.NewReporter(r.Config.Format, me)
+	if err != nil {
+		res.err = err
+		return res
+	}
+
+	var buf bytes.Buffer
+	reporter.Fprint(&buf)
+	res.output = buf.Bytes()
+
+	return res
+}
+
+// forEach calls fn for every index from 0 to n-1 on a pool of workers (GOMAXPROCS when workers <= 0),
+// returning once every call returned
+func forEach(n, workers int, fn func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -0,0 +1,139 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRunner_Run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// every tenth mission is broken, the others having a single robot ending up at x = i % 6
+	var paths []string
+	var want strings.Builder
+	for i := 0; i < 50; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("mission-%02d.txt", i))
+		mission := fmt.Sprintf("5 3\n0 0 E\n%s\n", strings.Repeat("F", i%6))
+		if i%10 == 3 {
+			mission = "5 3\n0 0 E\nFXF\n"
+		} else {
+			fmt.Fprintf(&want, "== %s\n%d 0 E\n", path, i%6)
+		}
+		if err := ioutil.WriteFile(path, []byte(mission), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	var out bytes.Buffer
+	metrics := domain.NewMetrics(nil)
+	stats, err := Runner{Workers: 4, Metrics: metrics}.Run(context.Background(), paths, &out)
+
+	if out.String() != want.String() {
+		t.Errorf("Run() got %s, want %s", out.String(), want.String())
+	}
+
+	errs, ok := err.(RunErrors)
+	if !ok || len(errs) != 5 || errs[0].Path != paths[3] || errs[4].Path != paths[43] {
+		t.Errorf("Run() error = %v, want the 5 broken missions in order", err)
+	}
+
+	if stats.Missions != 50 || stats.Failed != 5 || stats.Robots != 45 || stats.Steps != 108 {
+		t.Errorf("Run() got stats %+v", stats)
+	}
+
+	// missions without instructions have no robot deployed
+	got := metrics.Stats()
+	if got.MissionsRun != 45 || got.RobotsDeployed != 36 || got.Commands[domain.CommandForward] != 108 || got.Duration.Count != 45 {
+		t.Errorf("Run() got metrics %+v", got)
+	}
+}
+
+func TestRunner_Run_interrupted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out bytes.Buffer
+	stats, err := Runner{}.Run(ctx, []string{"../../test/golden/sample-1.input", "../../test/golden/sample-1.input"}, &out)
+	if errs, ok := err.(RunErrors); !ok || len(errs) != 2 || errs[0].Err != context.Canceled {
+		t.Errorf("Run() error = %v, want every mission canceled", err)
+	}
+	if out.Len() != 0 || stats.Failed != 2 {
+		t.Errorf("Run() got %s, stats %+v", out.String(), stats)
+	}
+
+	stats, err = Runner{MaxSteps: 10}.Run(context.Background(), []string{"../../test/golden/sample-1.input"}, &out)
+	if err == nil || stats.Steps != 10 {
+		t.Errorf("Run() got error %v, stats %+v, want the mission stopped after 10 steps", err, stats)
+	}
+}
+
+func Test_forEach(t *testing.T) {
+	var mu sync.Mutex
+	running, most := 0, 0
+	done := make([]bool, 100)
+
+	forEach(len(done), 3, func(i int) {
+		mu.Lock()
+		running++
+		if running > most {
+			most = running
+		}
+		mu.Unlock()
+
+		done[i] = true
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+	})
+
+	if most > 3 {
+		t.Errorf("forEach() ran %d calls at once, want at most 3", most)
+	}
+	for i, d := range done {
+		if !d {
+			t.Errorf("forEach() didn't call %d", i)
+		}
+	}
+}
+
+func TestRunMissions(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := RunMissions([]string{"-workers", "2", "../../test/golden/sample-1.input", "./missing.txt"}, &stdout, &stderr)
+	if code != 1 {
+		t.Errorf("RunMissions() got code %d, want 1", code)
+	}
+	if stdout.String() != "== ../../test/golden/sample-1.input\n1 1 E\n3 3 N LOST\n2 3 S\n" {
+		t.Errorf("RunMissions() got %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "./missing.txt: unable to read instructions") ||
+		!strings.Contains(stderr.String(), "2 missions (1 failed), 3 robots, 26 steps in") {
+		t.Errorf("RunMissions() got %q on stderr", stderr.String())
+	}
+
+	if code := RunMissions(nil, &stdout, ioutil.Discard); code != 2 {
+		t.Errorf("RunMissions() got code %d without paths, want 2", code)
+	}
+	if code := RunMissions([]string{"-format", "xml", "a.txt"}, &stdout, ioutil.Discard); code != 2 {
+		t.Errorf("RunMissions() got code %d with an unsupported format, want 2", code)
+	}
+
+	stdout.Reset()
+	RunMissions([]string{"-format", "json", "../../test/golden/sample-1.input"}, &stdout, ioutil.Discard)
+	if !strings.Contains(stdout.String(), `"status": "lost"`) {
+		t.Errorf("RunMissions() got %q with the json format", stdout.String())
+	}
+}
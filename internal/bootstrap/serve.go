@@ -0,0 +1,67 @@
+package bootstrap
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/server"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout is how long in-flight requests are given to complete on shutdown
+const shutdownTimeout = 10 * time.Second
+
+// Serve runs the HTTP API (see server.Server) until interrupted, completing in-flight requests and missions before exiting
+func Serve(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	config := NewConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	c, err := config.Config(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	logger := c.logger(stderr)
+	api := server.New(c.builder(logger), logger)
+	srv := &http.Server{Addr: *addr, Handler: api}
+	// event streams last as long as their mission, end them for the shutdown not to wait for them
+	srv.RegisterOnShutdown(api.Close)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(stop)
+
+	errs := make(chan error, 1)
+	go func() {
+		logger.WithField("addr", *addr).Info("serving missions")
+		errs <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		fmt.Fprintln(stderr, err)
+		return 1
+	case <-stop:
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		fmt.Fprintf(stderr, "failed to shut down gracefully, %q\n", err)
+		return 1
+	}
+	api.Wait()
+	logger.Info("server stopped")
+
+	return 0
+}
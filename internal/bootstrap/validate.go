@@ -0,0 +1,42 @@
+package bootstrap
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Validate dry-runs a mission and prints what is going to happen to its robots
+// it exits with 1 when there is anything to report and 2 when the mission can't be read
+func Validate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("input-path", "", "mission to validate")
+	config := NewConfigFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	c, err := config.Config(os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	me, err := load(*path, c, c.logger(stderr))
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	findings := me.Analyze()
+	for _, f := range findings {
+		fmt.Fprintln(stdout, f.String())
+	}
+
+	if len(findings) > 0 {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,64 @@
+package bootstrap
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	safe, err := ioutil.TempFile("", "mission")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(safe.Name())
+	_, _ = safe.WriteString("5 3\n1 1 E\nRFRFRFRF\n")
+	safe.Close()
+
+	tests := []struct {
+		name     string
+		args     []string
+		want     string
+		wantCode int
+	}{
+		{
+			name:     "mission with findings",
+			args:     []string{"-input-path", "../../test/golden/sample-1.input"},
+			want:     "robot #2: instruction 7: lost (edge) from 3 3 N reaching 3 4\nrobot #3: instruction 6: forward move ignored because of a scent at 3 3 N\n",
+			wantCode: 1,
+		},
+		{
+			name:     "edge policy from the flags",
+			args:     []string{"-input-path", "../../test/golden/sample-1.input", "-edge-policy", "stop"},
+			want:     "robot #2: instruction 7: forward move ignored because of a edge at 3 3 N\nrobot #3: instruction 6: forward move ignored because of a edge at 3 3 N\n",
+			wantCode: 1,
+		},
+		{
+			name:     "invalid config",
+			args:     []string{"-input-path", "../../test/golden/sample-1.input", "-scent-mode", "everywhere"},
+			wantCode: 2,
+		},
+		{
+			name: "mission without findings",
+			args: []string{"-input-path", safe.Name()},
+		},
+		{
+			name:     "missing mission",
+			args:     []string{"-input-path", "./missing.txt"},
+			wantCode: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			code := Validate(tt.args, &stdout, ioutil.Discard)
+			if code != tt.wantCode {
+				t.Errorf("Validate() got code %d, want %d", code, tt.wantCode)
+			}
+			if stdout.String() != tt.want {
+				t.Errorf("Validate() got %q, want %q", stdout.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// DefaultWatchInterval is how often a Watcher reads the mission file, unless set otherwise
+const DefaultWatchInterval = 500 * time.Millisecond
+
+// clearScreen moves the cursor home and clears the terminal
+const clearScreen = "\033[H\033[2J"
+
+// Watcher explores a mission file again every time its content changes, redrawing its report or its map
+type Watcher struct {
+	Path string
+	// Interval is how often the file is read, DefaultWatchInterval when 0
+	Interval time.Duration
+	Config   Config
+	// Map draws the map of the explored surface instead of the report
+	Map bool
+	Out io.Writer
+}
+
+// Watch polls the mission file until the context is done, reading or parse errors being drawn in place of the report
+func (w Watcher) Watch(ctx context.Context) {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// state is the file content or why it can't be read, the mission being drawn again whenever it changes
+	last := ""
+	for first := true; ; first = false {
+		content, err := ioutil.ReadFile(w.Path)
+		state := string(content)
+		if err != nil {
+			state = "error: " + err.Error()
+		}
+		if first || state != last {
+			w.draw(content, err)
+		}
+		last = state
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// draw clears the terminal and draws the report or the map of the mission, or why it can't be explored
+func (w Watcher) draw(content []byte, readErr error) {
+	fmt.Fprint(w.Out, clearScreen)
+	fmt.Fprintf(w.Out, "== %s, explored at %s\n", w.Path, time.Now().Format("15:04:05"))
+
+	output, err := w.explore(content, readErr)
+	if err != nil {
+		fmt.Fprintf(w.Out, "error: %s\n", err)
+	} else {
+		fmt.Fprint(w.Out, output)
+	}
+	fmt.Fprintln(w.Out, "-- watching for changes, ctrl+c to stop")
+}
+
+// explore explores the mission read from the file, returning its report or its map
+// the content drawn is the one compared with the previous read, the file not being read again
+func (w Watcher) explore(content []byte, readErr error) (string, error) {
+	if readErr != nil {
+		return "", readErr
+	}
+
+	lines, err := contentToStringArray(bytes.NewReader(content))
+	if err != nil {
+		return "", fmt.Errorf(`unable to read instructions from path "%s" - got %q`, w.Path, err)
+	}
+	builder := w.Config.builder(newLogger(ioutil.Discard))
+	me, err := builder.Build(lines)
+	if err != nil {
+		return "", fmt.Errorf("failed to prepare the exploration, %q", err)
+	}
+	me.SendInstructions()
+
+	if w.Map {
+		return strings.Join(me.Map(), "\n") + "\n", nil
+	}
+
+	reporter, err := domain.NewReporter(w.Config.Format, me)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	reporter.Fprint(&buf)
+
+	return buf.String(), nil
+}
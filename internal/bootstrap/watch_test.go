@@ -0,0 +1,122 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe to be read while written
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// waitFor waits for the output to contain want
+func waitFor(t *testing.T, out *syncBuffer, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(out.String(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Watch() never drew %q, got:\n%s", want, out.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWatcher_Watch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "mission.txt")
+	// written as a whole, the watcher never reading a file half written
+	write := func(content string) {
+		if err := ioutil.WriteFile(path+".tmp", []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		isMap bool
+		steps []struct{ content, want string }
+	}{
+		{
+			name: "report",
+			steps: []struct{ content, want string }{
+				{content: "5 3\n1 1 E\nF\n", want: "2 1 E\n"},
+				{content: "5 3\n1 1 E\nFF\n", want: "3 1 E\n"},
+				{content: "5 3\n1 1 E\nFXF\n", want: "error: failed to prepare the exploration"},
+				{content: "5 3\n1 1 E\nFFFFFF\n", want: "5 1 E LOST\n"},
+			},
+		},
+		{
+			name:  "map",
+			isMap: true,
+			steps: []struct{ content, want string }{
+				{content: "2 1\n0 0 N\nF\n", want: "^..\n...\n"},
+				{content: "2 1\n0 0 E\nF\n", want: "...\n.>.\n"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(path)
+			out := &syncBuffer{}
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				Watcher{Path: path, Interval: time.Millisecond, Map: tt.isMap, Out: out}.Watch(ctx)
+			}()
+
+			waitFor(t, out, "error: open "+path)
+			for _, s := range tt.steps {
+				write(s.content)
+				waitFor(t, out, s.want)
+			}
+
+			cancel()
+			<-done
+			if got := strings.Count(out.String(), clearScreen); got != len(tt.steps)+1 {
+				t.Errorf("Watch() drew %d times, want %d", got, len(tt.steps)+1)
+			}
+		})
+	}
+}
+
+func TestWatcher_explore(t *testing.T) {
+	// the content compared with the previous read is the one explored, the file isn't read again
+	w := Watcher{Path: "./missing.txt"}
+	got, err := w.explore([]byte("5 3\n1 1 E\nF\n"), nil)
+	if err != nil {
+		t.Fatalf("explore() error = %v", err)
+	}
+	if got != "2 1 E\n" {
+		t.Errorf("explore() got %q, want %q", got, "2 1 E\n")
+	}
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	BlockedByScent   = "scent"
+	BlockedByTerrain = "terrain"
+	// BlockedByEdge is a forward move off the grid ignored given the EdgeStop policy
+	BlockedByEdge = "edge"
+
+	FindingLost         = "lost"
+	FindingDepleted     = "depleted"
+	FindingBlocked      = "blocked"
+	FindingOffGridStart = "off-grid-start"
+)
+
+// noInstruction is the instruction index of a finding which isn't related to an instruction
+const noInstruction = -1
+
+// Finding is something worth knowing about a mission before sending it
+type Finding struct {
+	Kind string
+	// Robot is the robot index within the mission
+	Robot int
+	Label string
+	// Instruction is the index within the robot instructions, -1 when not related to an instruction
+	Instruction int
+	Message     string
+}
+
+// String returns the finding as a single line, ie: "robot #2: instruction 7: lost over the edge reaching 3 4"
+func (f Finding) String() string {
+	if f.Instruction == noInstruction {
+		return fmt.Sprintf("robot %s: %s", f.Label, f.Message)
+	}
+
+	return fmt.Sprintf("robot %s: instruction %d: %s", f.Label, f.Instruction, f.Message)
+}
+
+// Analyze explores a copy of the mission, leaving it untouched, and returns what is going to happen to the robots:
+// robots starting off the grid (which are skipped), getting lost or depleted and forward moves
+// ignored because of a scent or a terrain
+func (m *MarsExplorer) Analyze() []Finding {
+	findings := make([]Finding, 0)
+
+	explored := m.Copy()
+	explored.Observe(&blockedRecorder{findings: &findings, explorer: explored})
+
+	for i := range explored.Robots {
+		if explored.isRobotOffBound(explored.Robots[i]) {
+			findings = append(findings, Finding{
+				Kind:        FindingOffGridStart,
+				Robot:       i,
+				Label:       explored.Robots[i].Label(i),
+				Instruction: noInstruction,
+				Message:     fmt.Sprintf("starts off the grid at %d %d and will be skipped", explored.Robots[i].PosX, explored.Robots[i].PosY),
+			})
+		}
+	}
+
+	explored.SendInstructions()
+
+	for i, r := range explored.Robots {
+		if r.Loss == nil {
+			continue
+		}
+
+		f := Finding{
+			Robot:       i,
+			Label:       r.Label(i),
+			Instruction: r.Loss.Instruction,
+		}
+		if r.Lost {
+			f.Kind = FindingLost
+			f.Message = fmt.Sprintf("lost (%s) from %d %d %s reaching %d %d", r.Loss.Cause, r.PosX, r.PosY, r.Direction, r.Loss.Target.X, r.Loss.Target.Y)
+		} else {
+			f.Kind = FindingDepleted
+			f.Message = fmt.Sprintf("depleted at %d %d %s", r.PosX, r.PosY, r.Direction)
+		}
+		findings = append(findings, f)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Robot < findings[j].Robot
+	})
+
+	return findings
+}
+
+// blockedRecorder turns ignored forward moves into findings
+type blockedRecorder struct {
+	NopObserver
+	findings *[]Finding
+	explorer *MarsExplorer
+}
+
+// Blocked records a FindingBlocked
+func (b *blockedRecorder) Blocked(e BlockedEvent) {
+	*b.findings = append(*b.findings, Finding{
+		Kind:        FindingBlocked,
+		Robot:       e.Robot,
+		Label:       b.explorer.Robots[e.Robot].Label(e.Robot),
+		Instruction: e.Instruction,
+		Message:     fmt.Sprintf("forward move ignored because of a %s at %d %d %s", e.By, e.Pose.X, e.Pose.Y, e.Pose.Direction),
+	})
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarsExplorer_Analyze(t *testing.T) {
+	m := &MarsExplorer{
+		Surface: &Surface{MaxX: 5, MaxY: 3, Terrain: map[Point]string{{X: 1, Y: 0}: TerrainRock}},
+		Robots: []Robot{
+			{PosX: 3, PosY: 2, Direction: "N", Instructions: []string{"F", "R", "R", "F", "L", "L", "F", "F", "R", "R", "F", "L", "L"}},
+			{ID: "scout-1", PosX: 0, PosY: 3, Direction: "W", Instructions: []string{"L", "L", "F", "F", "F", "L", "F", "L", "F", "L"}},
+			{PosX: 6, PosY: 1, Direction: "N", Instructions: []string{"F"}},
+			{PosX: 0, PosY: 0, Direction: "E", Instructions: []string{"F", "L", "F", "F"}, Battery: &Battery{Level: 3}},
+			{PosX: 2, PosY: 2, Direction: "N", Instructions: []string{"R", "F"}},
+		},
+	}
+	before := m.Copy()
+
+	want := []Finding{
+		{Kind: FindingLost, Robot: 0, Label: "#1", Instruction: 7, Message: "lost (edge) from 3 3 N reaching 3 4"},
+		{Kind: FindingBlocked, Robot: 1, Label: "scout-1", Instruction: 6, Message: "forward move ignored because of a scent at 3 3 N"},
+		{Kind: FindingOffGridStart, Robot: 2, Label: "#3", Instruction: -1, Message: "starts off the grid at 6 1 and will be skipped"},
+		{Kind: FindingBlocked, Robot: 3, Label: "#4", Instruction: 0, Message: "forward move ignored because of a terrain at 0 0 E"},
+		{Kind: FindingDepleted, Robot: 3, Label: "#4", Instruction: 3, Message: "depleted at 0 1 N"},
+	}
+
+	got := m.Analyze()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyze() got %v, want %v", got, want)
+	}
+
+	if !reflect.DeepEqual(m, before) {
+		t.Errorf("Analyze() changed the mission, got %v, want %v", m, before)
+	}
+
+	if got[2].String() != "robot #3: starts off the grid at 6 1 and will be skipped" {
+		t.Errorf("Finding.String() got %s", got[2].String())
+	}
+	if got[0].String() != "robot #1: instruction 7: lost (edge) from 3 3 N reaching 3 4" {
+		t.Errorf("Finding.String() got %s", got[0].String())
+	}
+}
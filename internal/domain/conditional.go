@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// SensorScent is true when a forward move from the robot's pose is guarded by a robot scent
+	SensorScent = "S"
+	// SensorBlocked is true when the grid point ahead is occupied by another robot still on the surface
+	// or by a terrain the robot can't move onto
+	SensorBlocked = "B"
+	// SensorOffGrid is true when the grid point ahead is outside of the surface
+	SensorOffGrid = "O"
+
+	conditionalOpen  = "["
+	conditionalClose = "]"
+	conditionalThen  = "?"
+	conditionalElse  = ":"
+)
+
+// Conditional is an instruction evaluated against the live surface state
+// written as [<sensor>?<then>:<else>], the else branch being optional, ie: [O?R:F] or [S?L]
+// branches can contain any instruction, including other conditionals
+type Conditional struct {
+	Sensor string
+	Then   []string
+	Else   []string
+}
+
+// isConditional tells if a given instruction is a conditional one
+func isConditional(c string) bool {
+	return strings.HasPrefix(c, conditionalOpen)
+}
+
+// ParseConditional takes a conditional instruction and returns its Conditional representation or an error
+func ParseConditional(c string) (*Conditional, error) {
+	if !strings.HasPrefix(c, conditionalOpen) || !strings.HasSuffix(c, conditionalClose) {
+		return nil, fmt.Errorf("conditional %q must be enclosed in %s%s", c, conditionalOpen, conditionalClose)
+	}
+
+	body := c[1 : len(c)-1]
+	if len(body) < 2 || body[1:2] != conditionalThen {
+		return nil, fmt.Errorf("conditional %q must start with a sensor followed by %s", c, conditionalThen)
+	}
+
+	sensor := body[0:1]
+	switch sensor {
+	case SensorScent, SensorBlocked, SensorOffGrid:
+	default:
+		return nil, fmt.Errorf("unsupported sensor %s in conditional %q", sensor, c)
+	}
+
+	branches := body[2:]
+	split := len(branches)
+	depth := 0
+	for i := 0; i < len(branches); i++ {
+		switch branches[i : i+1] {
+		case conditionalOpen:
+			depth++
+		case conditionalClose:
+			depth--
+		case conditionalElse:
+			if depth == 0 && split == len(branches) {
+				split = i
+			}
+		}
+	}
+
+	then, err := TokenizeInstructions(branches[:split])
+	if err != nil {
+		return nil, fmt.Errorf("invalid then branch in conditional %q, got %q", c, err)
+	}
+
+	var otherwise []string
+	if split < len(branches) {
+		otherwise, err = TokenizeInstructions(branches[split+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid else branch in conditional %q, got %q", c, err)
+		}
+	}
+
+	return &Conditional{
+		Sensor: sensor,
+		Then:   then,
+		Else:   otherwise,
+	}, nil
+}
+
+// TokenizeInstructions splits a line of instructions into single instructions
+// every letter is an instruction on its own while a conditional is kept as a whole, ie: "F[O?R]F" gives F, [O?R], F
+func TokenizeInstructions(line string) ([]string, error) {
+	instructions := make([]string, 0, len(line))
+	start, depth := 0, 0
+	for i := 0; i < len(line); i++ {
+		switch line[i : i+1] {
+		case conditionalOpen:
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case conditionalClose:
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unexpected %s at position %d", conditionalClose, i)
+			}
+			if depth == 0 {
+				c := line[start : i+1]
+				if _, err := ParseConditional(c); err != nil {
+					return nil, err
+				}
+				instructions = append(instructions, c)
+			}
+		default:
+			if depth == 0 {
+				instructions = append(instructions, line[i:i+1])
+			}
+		}
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("unterminated conditional starting at position %d", start)
+	}
+
+	return instructions, nil
+}
+
+// branch returns the instructions to run given the sensor reading
+func (c *Conditional) branch(reading bool) []string {
+	if reading {
+		return c.Then
+	}
+
+	return c.Else
+}
+
+// sense reads the given sensor for a robot against the current state of the surface
+func (m *MarsExplorer) sense(r *Robot, sensor string) bool {
+	switch sensor {
+	case SensorScent:
+		return m.isThereARobotScent(*r, CommandForward)
+	case SensorBlocked:
+		x, y, ok := r.ahead()
+		if !ok {
+			return false
+		}
+		if m.terrainEffect(r, x, y) == TerrainBlock {
+			return true
+		}
+		for i := range m.Robots {
+			o := &m.Robots[i]
+			if o == r {
+				continue
+			}
+			if !o.Lost && o.PosX == x && o.PosY == y && m.Surface.Contains(x, y) {
+				return true
+			}
+		}
+		return false
+	case SensorOffGrid:
+		x, y, ok := r.ahead()
+		return ok && !m.Surface.Contains(x, y)
+	default:
+		return false
+	}
+}
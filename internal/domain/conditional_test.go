@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenizeInstructions(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "plain instructions are split by letter",
+			line: "RFL",
+			want: []string{"R", "F", "L"},
+		},
+		{
+			name: "conditionals are kept as a whole",
+			line: "F[O?R:F]F",
+			want: []string{"F", "[O?R:F]", "F"},
+		},
+		{
+			name: "nested conditionals are kept as a whole",
+			line: "[O?R:[S?L:F]]F",
+			want: []string{"[O?R:[S?L:F]]", "F"},
+		},
+		{
+			name:    "unterminated conditional",
+			line:    "F[O?R",
+			wantErr: true,
+		},
+		{
+			name:    "unexpected closing bracket",
+			line:    "FR]",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported sensor",
+			line:    "[X?R]",
+			wantErr: true,
+		},
+		{
+			name:    "missing sensor",
+			line:    "[R]",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TokenizeInstructions(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TokenizeInstructions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TokenizeInstructions() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConditional(t *testing.T) {
+	tests := []struct {
+		name    string
+		c       string
+		want    *Conditional
+		wantErr bool
+	}{
+		{
+			name: "then and else branches",
+			c:    "[S?RF:F]",
+			want: &Conditional{Sensor: SensorScent, Then: []string{"R", "F"}, Else: []string{"F"}},
+		},
+		{
+			name: "else branch is optional",
+			c:    "[B?L]",
+			want: &Conditional{Sensor: SensorBlocked, Then: []string{"L"}},
+		},
+		{
+			name: "else branch belongs to the outer conditional",
+			c:    "[O?[S?L:R]:F]",
+			want: &Conditional{Sensor: SensorOffGrid, Then: []string{"[S?L:R]"}, Else: []string{"F"}},
+		},
+		{
+			name:    "not a conditional",
+			c:       "F",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseConditional(tt.c)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseConditional() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConditional() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarsExplorer_SendInstructions_Conditionals(t *testing.T) {
+	tests := []struct {
+		name   string
+		scents []Scent
+		robots []Robot
+		want   []Robot
+	}{
+		{
+			name: "robot turns away from the edge instead of getting lost",
+			robots: []Robot{
+				{PosX: 0, PosY: 2, Direction: "N", Instructions: []string{"[O?R:F]", "F"}},
+			},
+			want: []Robot{
+				{PosX: 1, PosY: 2, Direction: "E", Instructions: []string{"[O?R:F]", "F"}, Cursor: 2},
+			},
+		},
+		{
+			name: "robot takes the else branch when on the grid",
+			robots: []Robot{
+				{PosX: 0, PosY: 0, Direction: "N", Instructions: []string{"[O?R:F]"}},
+			},
+			want: []Robot{
+				{PosX: 0, PosY: 1, Direction: "N", Instructions: []string{"[O?R:F]"}, Cursor: 1},
+			},
+		},
+		{
+			name:   "robot reacts to a scent",
+			scents: []Scent{{PosX: 1, PosY: 2, Direction: "N"}},
+			robots: []Robot{
+				{PosX: 1, PosY: 2, Direction: "N", Instructions: []string{"[S?L]", "F"}},
+			},
+			want: []Robot{
+				{PosX: 0, PosY: 2, Direction: "W", Instructions: []string{"[S?L]", "F"}, Cursor: 2},
+			},
+		},
+		{
+			name: "robot goes around another robot",
+			robots: []Robot{
+				{PosX: 1, PosY: 1, Direction: "N"},
+				{PosX: 0, PosY: 1, Direction: "E", Instructions: []string{"[B?LFRFFRFL:F]"}},
+			},
+			want: []Robot{
+				{PosX: 1, PosY: 1, Direction: "N"},
+				{PosX: 2, PosY: 1, Direction: "E", Instructions: []string{"[B?LFRFFRFL:F]"}, Cursor: 1},
+			},
+		},
+		{
+			name: "robot can get lost within a conditional branch",
+			robots: []Robot{
+				{PosX: 2, PosY: 1, Direction: "E", Instructions: []string{"[S?R:FF]", "L"}},
+			},
+			want: []Robot{
+				{PosX: 2, PosY: 1, Direction: "E", Lost: true, Loss: &Loss{Cause: LossCauseEdge, Instruction: 0, Target: Point{X: 3, Y: 1}}, Instructions: []string{"[S?R:FF]", "L"}, Cursor: 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MarsExplorer{
+				Surface: &Surface{MaxX: 2, MaxY: 2},
+				Robots:  tt.robots,
+				Scents:  tt.scents,
+			}
+
+			m.SendInstructions()
+
+			if !reflect.DeepEqual(m.Robots, tt.want) {
+				t.Errorf("SendInstructions() got %v, want %v", m.Robots, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package domain
+
+import "fmt"
+
+// Coverage is the outcome of planning the exploration of the whole surface by a fleet of robots
+type Coverage struct {
+	// Robots are the given robots holding their planned instructions
+	Robots []Robot
+	// Visited are the grid points the robots go through
+	Visited map[Point]bool
+	// Cells is the number of grid points of the surface
+	Cells int
+}
+
+// Percent returns the share of the surface visited by the robots
+func (c *Coverage) Percent() float64 {
+	if c.Cells == 0 {
+		return 0
+	}
+
+	return float64(len(c.Visited)) * 100 / float64(c.Cells)
+}
+
+// Cover plans instructions for the given robots to visit every grid point of the surface
+// robots take turns heading to the nearest grid point which hasn't been visited yet
+// until none of them can reach one within the limit of instructions
+// planned instructions never risk an off-grid step, a crater or a scent
+func (p *Planner) Cover(robots []Robot, limit int) (*Coverage, error) {
+	c := &Coverage{
+		Robots:  make([]Robot, len(robots)),
+		Visited: make(map[Point]bool),
+		Cells:   (p.Surface.MaxX + 1) * (p.Surface.MaxY + 1),
+	}
+
+	poses := make([]Pose, len(robots))
+	for i, r := range robots {
+		if !p.Surface.Contains(r.PosX, r.PosY) {
+			return nil, fmt.Errorf("robot %s starts outside of the surface", r.Label(i))
+		}
+		if !r.Supports(CommandForward) {
+			return nil, fmt.Errorf("robot %s can't move forward", r.Label(i))
+		}
+
+		c.Robots[i] = r
+		c.Robots[i].Instructions = make([]string, 0)
+		poses[i] = Pose{X: r.PosX, Y: r.PosY, Direction: r.Direction}
+		c.Visited[Point{X: r.PosX, Y: r.PosY}] = true
+	}
+
+	done := make([]bool, len(robots))
+	for progress := true; progress; {
+		progress = false
+		for i := range c.Robots {
+			if done[i] {
+				continue
+			}
+
+			planner := *p
+			planner.Type = c.Robots[i].Type
+			instructions, end, ok := planner.search(poses[i], func(pose Pose) bool {
+				return !c.Visited[Point{X: pose.X, Y: pose.Y}]
+			})
+			if !ok || len(c.Robots[i].Instructions)+len(instructions) > limit {
+				done[i] = true
+				continue
+			}
+
+			for _, point := range planner.trace(poses[i], instructions) {
+				c.Visited[point] = true
+			}
+			c.Robots[i].Instructions = append(c.Robots[i].Instructions, instructions...)
+			poses[i] = end
+			progress = true
+		}
+	}
+
+	return c, nil
+}
+
+// trace returns every grid point a robot goes through when executing instructions from a pose
+func (p *Planner) trace(start Pose, instructions []string) []Point {
+	r := &Robot{Type: p.Type, PosX: start.X, PosY: start.Y, Direction: start.Direction}
+	points := make([]Point, 0)
+	for _, c := range instructions {
+		steps := 1
+		if c == CommandForward {
+			steps = r.Capabilities().Speed
+		}
+		for s := 0; s < steps; s++ {
+			_ = r.Execute(c)
+			points = append(points, Point{X: r.PosX, Y: r.PosY})
+		}
+	}
+
+	return points
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestPlanner_Cover(t *testing.T) {
+	tests := []struct {
+		name        string
+		surface     *Surface
+		scents      []Scent
+		robots      []Robot
+		limit       int
+		wantPercent float64
+		wantErr     bool
+	}{
+		{
+			name:        "single robot covers a small surface",
+			surface:     &Surface{MaxX: 2, MaxY: 2},
+			robots:      []Robot{{PosX: 0, PosY: 0, Direction: "N"}},
+			limit:       MaxInstructions,
+			wantPercent: 100,
+		},
+		{
+			name:    "fleet covers a surface with terrain and scents",
+			surface: &Surface{MaxX: 5, MaxY: 3, Terrain: map[Point]string{{X: 2, Y: 2}: TerrainRock, {X: 4, Y: 1}: TerrainCrater}},
+			scents:  []Scent{{PosX: 3, PosY: 3, Direction: "N"}},
+			robots: []Robot{
+				{PosX: 0, PosY: 0, Direction: "N"},
+				{PosX: 5, PosY: 3, Direction: "S", Type: RobotTypeHover},
+				{PosX: 3, PosY: 0, Direction: "W", Type: RobotTypeTracked},
+			},
+			limit:       MaxInstructions,
+			wantPercent: 100,
+		},
+		{
+			name:        "instructions limit leaves part of the surface unvisited",
+			surface:     &Surface{MaxX: 9, MaxY: 9},
+			robots:      []Robot{{PosX: 0, PosY: 0, Direction: "N"}},
+			limit:       12,
+			wantPercent: 12,
+		},
+		{
+			name:    "robot starting outside of the surface",
+			surface: &Surface{MaxX: 2, MaxY: 2},
+			robots:  []Robot{{PosX: 3, PosY: 0, Direction: "N"}},
+			limit:   MaxInstructions,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Planner{Surface: tt.surface, Scents: tt.scents}
+			got, err := p.Cover(tt.robots, tt.limit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Cover() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Percent() != tt.wantPercent {
+				t.Errorf("Cover() got %.1f%% coverage, want %.1f%%", got.Percent(), tt.wantPercent)
+			}
+
+			// the planned instructions must be safe and visit what the coverage claims
+			m := &MarsExplorer{Surface: tt.surface, Scents: tt.scents, Robots: got.Robots}
+			visited := make(map[Point]bool)
+			for i := range m.Robots {
+				r := &m.Robots[i]
+				if len(r.Instructions) > tt.limit {
+					t.Errorf("Cover() robot %d got %d instructions, want at most %d", i, len(r.Instructions), tt.limit)
+				}
+				visited[Point{X: r.PosX, Y: r.PosY}] = true
+				planner := &Planner{Surface: tt.surface, Type: r.Type}
+				for _, point := range planner.trace(Pose{X: r.PosX, Y: r.PosY, Direction: r.Direction}, r.Instructions) {
+					visited[point] = true
+				}
+			}
+			m.SendInstructions()
+			for _, r := range m.Robots {
+				if r.Lost {
+					t.Errorf("Cover() robot got lost, %s", r.ToString())
+				}
+			}
+			if len(visited) != len(got.Visited) {
+				t.Errorf("Cover() robots visit %d grid points, want %d", len(visited), len(got.Visited))
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package domain
+
+import "fmt"
+
+// Step is the outcome of executing a single instruction of a robot
+type Step struct {
+	// Number is the number of steps executed so far, this one included
+	Number int
+	// Robot is the robot index within MarsExplorer.Robots
+	Robot int
+	Label string
+	// Instruction is the index within the robot instructions
+	Instruction int
+	Command     string
+	Before      Pose
+	After       Pose
+	// Events are the events sent while executing the instruction
+	Events []Event
+}
+
+// String returns the step as a single line, ie: "step 3: robot #1 instruction 2 F: 1 1 N -> 1 2 N"
+func (s *Step) String() string {
+	return fmt.Sprintf("step %d: robot %s instruction %d %s: %d %d %s -> %d %d %s", s.Number, s.Label, s.Instruction, s.Command,
+		s.Before.X, s.Before.Y, s.Before.Direction, s.After.X, s.After.Y, s.After.Direction)
+}
+
+// Breakpoint stops Debugger.Continue after a step matching all of its criteria, empty ones matching any step
+type Breakpoint struct {
+	// Robot is a robot label (see Robot.Label), matching every step of the robot
+	Robot string
+	// Cell matches a robot reaching the grid point
+	Cell *Point
+	// Event is an event name (see Event), matching a step sending such an event
+	Event string
+}
+
+// String returns the breakpoint criteria
+func (b Breakpoint) String() string {
+	s := ""
+	if b.Robot != "" {
+		s += fmt.Sprintf("robot %s ", b.Robot)
+	}
+	if b.Cell != nil {
+		s += fmt.Sprintf("cell %d %d ", b.Cell.X, b.Cell.Y)
+	}
+	if b.Event != "" {
+		s += fmt.Sprintf("event %s ", b.Event)
+	}
+	if s == "" {
+		return "any step"
+	}
+
+	return s[:len(s)-1]
+}
+
+// matches tells if a step matches the breakpoint
+func (b Breakpoint) matches(s *Step) bool {
+	if b.Robot != "" && b.Robot != s.Label {
+		return false
+	}
+
+	if b.Cell != nil {
+		reached := false
+		for _, e := range s.Events {
+			if m, ok := e.(MovedEvent); ok && m.To.X == b.Cell.X && m.To.Y == b.Cell.Y {
+				reached = true
+			}
+		}
+		if !reached {
+			return false
+		}
+	}
+
+	if b.Event != "" {
+		sent := false
+		for _, e := range s.Events {
+			if e.Name() == b.Event {
+				sent = true
+			}
+		}
+		if !sent {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Debugger executes a mission one instruction at a time, recording its history so it can step back to any earlier state
+type Debugger struct {
+	current     *MarsExplorer
+	history     []*MarsExplorer
+	steps       []*Step
+	recorder    *EventRecorder
+	Breakpoints []Breakpoint
+}
+
+// NewDebugger returns a Debugger starting from the current state of the mission, which it takes ownership of
+// observers registered on the mission are not carried over once stepping back
+func NewDebugger(m *MarsExplorer) *Debugger {
+	d := &Debugger{current: m, recorder: &EventRecorder{}}
+	m.Observe(d.recorder)
+
+	return d
+}
+
+// Explorer returns the current state of the mission
+func (d *Debugger) Explorer() *MarsExplorer {
+	return d.current
+}
+
+// Steps returns the steps executed so far, the last one being the most recent
+func (d *Debugger) Steps() []*Step {
+	return d.steps
+}
+
+// Step executes the next instruction of the mission, it returns false when there is nothing left to execute
+func (d *Debugger) Step() (*Step, bool) {
+	ri, ok := d.current.next()
+	if !ok {
+		return nil, false
+	}
+
+	d.history = append(d.history, d.current.Copy())
+	d.recorder.Events = nil
+
+	r := &d.current.Robots[ri]
+	s := &Step{
+		Number:      len(d.steps) + 1,
+		Robot:       ri,
+		Label:       r.Label(ri),
+		Instruction: r.Cursor,
+		Command:     r.Instructions[r.Cursor],
+		Before:      r.Pose(),
+	}
+	d.current.step(ri)
+	s.After = r.Pose()
+	s.Events = d.recorder.Events
+	d.steps = append(d.steps, s)
+
+	return s, true
+}
+
+// Back steps back n instructions, it returns the number of steps actually undone
+func (d *Debugger) Back(n int) int {
+	if n > len(d.history) {
+		n = len(d.history)
+	}
+	if n <= 0 {
+		return 0
+	}
+
+	d.Rewind(len(d.history) - n)
+
+	return n
+}
+
+// Rewind goes back to the state after the given number of steps, 0 being the initial state
+func (d *Debugger) Rewind(step int) {
+	if step < 0 || step >= len(d.history) {
+		return
+	}
+
+	d.current = d.history[step]
+	d.current.Observe(d.recorder)
+	d.history = d.history[:step]
+	d.steps = d.steps[:step]
+}
+
+// Continue executes the mission until a step matches one of the breakpoints or there is nothing left to execute
+// it returns the last step executed and the breakpoint it matched, if any
+func (d *Debugger) Continue() (*Step, *Breakpoint) {
+	var last *Step
+	for {
+		s, ok := d.Step()
+		if !ok {
+			return last, nil
+		}
+		last = s
+
+		for i := range d.Breakpoints {
+			if d.Breakpoints[i].matches(s) {
+				return s, &d.Breakpoints[i]
+			}
+		}
+	}
+}
+
+// next returns the index of the robot whose instruction is the next one to execute
+// robots are explored one after the other, robots starting off the grid being skipped
+func (m *MarsExplorer) next() (int, bool) {
+	for r := range m.Robots {
+		if m.isRobotOffBound(m.Robots[r]) {
+			continue
+		}
+		if !m.Robots[r].Done() {
+			return r, true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+// sampleMission returns the mission of the README sample
+func sampleMission() *MarsExplorer {
+	return &MarsExplorer{
+		Surface: &Surface{MaxX: 5, MaxY: 3},
+		Robots: []Robot{
+			{PosX: 1, PosY: 1, Direction: "E", Instructions: []string{"R", "F", "R", "F", "R", "F", "R", "F"}},
+			{PosX: 3, PosY: 2, Direction: "N", Instructions: []string{"F", "R", "R", "F", "L", "L", "F", "F", "R", "R", "F", "L", "L"}},
+			{PosX: 0, PosY: 3, Direction: "W", Instructions: []string{"L", "L", "F", "F", "F", "L", "F", "L", "F", "L"}},
+		},
+	}
+}
+
+func TestDebugger_Step(t *testing.T) {
+	d := NewDebugger(sampleMission())
+
+	s, ok := d.Step()
+	if !ok {
+		t.Fatalf("Step() expected a step")
+	}
+	want := &Step{
+		Number:      1,
+		Robot:       0,
+		Label:       "#1",
+		Instruction: 0,
+		Command:     "R",
+		Before:      Pose{X: 1, Y: 1, Direction: "E"},
+		After:       Pose{X: 1, Y: 1, Direction: "S"},
+		Events:      []Event{MovedEvent{Robot: 0, Command: "R", From: Pose{X: 1, Y: 1, Direction: "E"}, To: Pose{X: 1, Y: 1, Direction: "S"}}},
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("Step() got %v, want %v", s, want)
+	}
+	if s.String() != "step 1: robot #1 instruction 0 R: 1 1 E -> 1 1 S" {
+		t.Errorf("Step.String() got %s", s.String())
+	}
+
+	// stepping through the whole mission ends up like SendInstructions
+	count := 1
+	for _, ok := d.Step(); ok; _, ok = d.Step() {
+		count++
+	}
+	if count != 8+8+10 {
+		t.Errorf("Step() got %d steps, want %d", count, 8+8+10)
+	}
+	m := sampleMission()
+	m.SendInstructions()
+	if !reflect.DeepEqual(d.Explorer().Robots, m.Robots) || !reflect.DeepEqual(d.Explorer().Scents, m.Scents) {
+		t.Errorf("Step() got %v, want %v", d.Explorer(), m)
+	}
+}
+
+func TestDebugger_Back(t *testing.T) {
+	d := NewDebugger(sampleMission())
+	for i := 0; i < 16; i++ {
+		d.Step()
+	}
+	if len(d.Explorer().Scents) != 1 {
+		t.Fatalf("Step() expected the second robot to be lost")
+	}
+
+	if n := d.Back(3); n != 3 {
+		t.Errorf("Back() got %d, want 3", n)
+	}
+	if len(d.Steps()) != 13 || len(d.Explorer().Scents) != 0 || d.Explorer().Robots[1].Lost {
+		t.Errorf("Back() didn't restore the state before the second robot got lost, %v", d.Explorer())
+	}
+
+	// stepping again after going back gives the same outcome
+	for i := 0; i < 3; i++ {
+		d.Step()
+	}
+	if !d.Explorer().Robots[1].Lost || d.Steps()[15].Number != 16 {
+		t.Errorf("Step() got %v after stepping back", d.Explorer().Robots[1])
+	}
+
+	if n := d.Back(100); n != 16 {
+		t.Errorf("Back() got %d, want 16", n)
+	}
+	if !reflect.DeepEqual(d.Explorer().Robots, sampleMission().Robots) {
+		t.Errorf("Back() got %v, want the initial state", d.Explorer().Robots)
+	}
+}
+
+func TestDebugger_Continue(t *testing.T) {
+	tests := []struct {
+		name       string
+		breakpoint Breakpoint
+		wantStep   int
+		wantHit    bool
+	}{
+		{
+			name:       "break on a robot",
+			breakpoint: Breakpoint{Robot: "#3"},
+			wantStep:   17,
+			wantHit:    true,
+		},
+		{
+			name:       "break on a cell",
+			breakpoint: Breakpoint{Cell: &Point{X: 3, Y: 3}},
+			wantStep:   9,
+			wantHit:    true,
+		},
+		{
+			name:       "break on an event",
+			breakpoint: Breakpoint{Event: EventBlocked},
+			wantStep:   23,
+			wantHit:    true,
+		},
+		{
+			name:       "break on a robot reaching a cell",
+			breakpoint: Breakpoint{Robot: "#3", Cell: &Point{X: 3, Y: 3}},
+			wantStep:   21,
+			wantHit:    true,
+		},
+		{
+			name:       "breakpoint never hit",
+			breakpoint: Breakpoint{Cell: &Point{X: 5, Y: 0}},
+			wantStep:   26,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDebugger(sampleMission())
+			d.Breakpoints = []Breakpoint{tt.breakpoint}
+
+			s, hit := d.Continue()
+			if (hit != nil) != tt.wantHit {
+				t.Errorf("Continue() got breakpoint %v, want hit %t", hit, tt.wantHit)
+			}
+			if s.Number != tt.wantStep {
+				t.Errorf("Continue() stopped at step %d, want %d", s.Number, tt.wantStep)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import "fmt"
+
+// EnergyCosts is the amount of energy drained by each command
+type EnergyCosts map[string]int
+
+// DefaultEnergyCosts moving forward costs more than turning on the spot
+var DefaultEnergyCosts = EnergyCosts{
+	CommandForward: 2,
+	CommandLeft:    1,
+	CommandRight:   1,
+}
+
+// Battery is the energy budget carried by a robot
+type Battery struct {
+	Level int
+	Costs EnergyCosts
+}
+
+// cost returns the energy required by a command, unknown commands don't cost anything
+func (c EnergyCosts) cost(command string) int {
+	if c == nil {
+		return DefaultEnergyCosts[command]
+	}
+
+	return c[command]
+}
+
+// drain takes the cost of a command from the robot battery (if any) before it gets executed
+// the robot gets depleted if there isn't enough energy left to execute it
+func (r *Robot) drain(c string) error {
+	if r.Battery == nil {
+		return nil
+	}
+
+	cost := r.Battery.Costs.cost(c)
+	if cost > r.Battery.Level {
+		r.Depleted = true
+		return fmt.Errorf("not enough energy to execute %s, %d left for a cost of %d", c, r.Battery.Level, cost)
+	}
+
+	r.Battery.Level -= cost
+
+	return nil
+}
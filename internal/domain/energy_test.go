@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRobot_Execute_Energy(t *testing.T) {
+	tests := []struct {
+		name         string
+		battery      *Battery
+		instructions []string
+		wantLevel    int
+		wantDepleted bool
+		wantPosY     int
+	}{
+		{
+			name:         "forward moves cost more than turns",
+			battery:      &Battery{Level: 10},
+			instructions: []string{"F", "R", "L", "F"},
+			wantLevel:    4,
+			wantPosY:     2,
+		},
+		{
+			name:         "custom costs are applied",
+			battery:      &Battery{Level: 10, Costs: EnergyCosts{CommandForward: 5, CommandLeft: 0, CommandRight: 0}},
+			instructions: []string{"F", "R", "L", "F"},
+			wantLevel:    0,
+			wantPosY:     2,
+		},
+		{
+			name:         "robot stops when it can't afford a command",
+			battery:      &Battery{Level: 3},
+			instructions: []string{"F", "F", "R"},
+			wantLevel:    1,
+			wantDepleted: true,
+			wantPosY:     1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Robot{Direction: DirectionNorth, Battery: tt.battery}
+
+			for _, c := range tt.instructions {
+				if err := r.Execute(c); err != nil {
+					break
+				}
+			}
+
+			if r.Battery.Level != tt.wantLevel {
+				t.Errorf("Robot.Execute() got level %d, want %d", r.Battery.Level, tt.wantLevel)
+			}
+			if r.Depleted != tt.wantDepleted {
+				t.Errorf("Robot.Execute() got depleted %t, want %t", r.Depleted, tt.wantDepleted)
+			}
+			if r.PosY != tt.wantPosY {
+				t.Errorf("Robot.Execute() got pos Y %d, want %d", r.PosY, tt.wantPosY)
+			}
+		})
+	}
+}
+
+func TestMarsExplorer_SendInstructions_Energy(t *testing.T) {
+	m := &MarsExplorer{
+		Surface: &Surface{MaxX: 5, MaxY: 5},
+		Robots: []Robot{
+			{PosX: 0, PosY: 0, Direction: "N", Instructions: []string{"F", "F", "R", "F"}, Battery: &Battery{Level: 5}},
+			{PosX: 0, PosY: 3, Direction: "E", Instructions: []string{"F", "F", "R", "F"}, Battery: &Battery{Level: 7}},
+		},
+	}
+
+	m.SendInstructions()
+
+	want := []Robot{
+		{PosX: 0, PosY: 2, Direction: "E", Instructions: []string{"F", "F", "R", "F"}, Battery: &Battery{Level: 0}, Depleted: true, Cursor: 4, Loss: &Loss{Cause: LossCauseEnergy, Instruction: 3, Target: Point{X: 1, Y: 2}}},
+		{PosX: 2, PosY: 2, Direction: "S", Instructions: []string{"F", "F", "R", "F"}, Battery: &Battery{Level: 0}, Cursor: 4},
+	}
+	if !reflect.DeepEqual(m.Robots, want) {
+		t.Errorf("SendInstructions() got %v, want %v", m.Robots, want)
+	}
+
+	if got := m.Robots[0].ToString(); got != "0 2 E DEPLETED" {
+		t.Errorf("ToString() got %s, want %s", got, "0 2 E DEPLETED")
+	}
+}
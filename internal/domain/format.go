@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CommentPrefix starts a comment line of a mission, comment lines being ignored by Build, ie: "# scouting the crater"
+const CommentPrefix = "#"
+
+// isComment tells if a mission line is a comment line
+func isComment(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), CommentPrefix)
+}
+
+// stripComments returns the lines of a mission without its comment lines, along with their line numbers
+func stripComments(lines []string) ([]string, []int) {
+	stripped := make([]string, 0, len(lines))
+	numbers := make([]int, 0, len(lines))
+	for i, l := range lines {
+		if !isComment(l) {
+			stripped = append(stripped, l)
+			numbers = append(numbers, i+1)
+		}
+	}
+
+	return stripped, numbers
+}
+
+// Format returns the lines of a mission in the canonical layout, the mission being read as Build would once formatted:
+// line endings and spaces are normalized, directions and instructions are upper case while terrain kinds and option
+// keys are lower case, terrain lines follow the surface line and robots are separated by a blank line
+// (as in the sample mission of the README).
+// Comment lines are kept before the line they preceded, trailing ones at the end.
+func (mb *MarsBuilder) Format(lines []string) ([]string, error) {
+	formatted := make([]string, 0, len(lines))
+	var comments []string
+	surface, robots := false, 0
+	for i, raw := range lines {
+		line := strings.TrimSpace(strings.TrimSuffix(raw, "\r"))
+		if line == "" {
+			continue
+		}
+		if isComment(line) {
+			comments = append(comments, line)
+			continue
+		}
+
+		fields := strings.Fields(line)
+		switch {
+		case !surface:
+			surface = true
+			line = strings.Join(fields, " ")
+		case len(fields) == 3 && (strings.ToLower(fields[0]) == TerrainRock || strings.ToLower(fields[0]) == TerrainCrater):
+			line = strings.ToLower(fields[0]) + " " + fields[1] + " " + fields[2]
+		case len(fields) >= 3:
+			line = formatPosition(fields)
+			if robots > 0 {
+				formatted = append(formatted, "")
+			}
+			robots++
+		case len(fields) == 1:
+			line = strings.ToUpper(line)
+		default:
+			return nil, fmt.Errorf("line %d: expected a surface, a terrain, a robot position or instructions, got %s", i+1, line)
+		}
+
+		formatted = append(formatted, comments...)
+		formatted = append(formatted, line)
+		comments = nil
+	}
+
+	if len(comments) > 0 {
+		formatted = append(formatted, "")
+		formatted = append(formatted, comments...)
+	}
+
+	if _, err := mb.Build(formatted); err != nil {
+		return nil, err
+	}
+
+	return formatted, nil
+}
+
+// formatPosition returns a robot position line in the canonical layout, ie: "1 1 e Type=Hover" as "1 1 E type=hover"
+// robot ids are case sensitive, they are kept as is
+func formatPosition(fields []string) string {
+	line := fields[0] + " " + fields[1] + " " + strings.ToUpper(fields[2])
+	for _, o := range fields[3:] {
+		kv := strings.SplitN(o, "=", 2)
+		kv[0] = strings.ToLower(kv[0])
+		if kv[0] == "type" && len(kv) == 2 {
+			kv[1] = strings.ToLower(kv[1])
+		}
+		line += " " + strings.Join(kv, "=")
+	}
+
+	return line
+}
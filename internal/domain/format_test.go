@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarsBuilder_Format(t *testing.T) {
+	tests := []struct {
+		name    string
+		mission string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "canonical",
+			mission: "5 3\n1 1 E\nRFRFRFRF\n\n3 2 N\nFRRFLLFFRRFLL",
+			want:    "5 3\n1 1 E\nRFRFRFRF\n\n3 2 N\nFRRFLLFFRRFLL",
+		},
+		{
+			name:    "blank lines, trailing spaces and CRLF",
+			mission: "\r\n5 3  \r\n\r\n\r\n1 1 E\r\n  RFRFRFRF\r\n3 2 N\r\n\r\nFRRF\r\n\r\n\r\n",
+			want:    "5 3\n1 1 E\nRFRFRFRF\n\n3 2 N\nFRRF",
+		},
+		{
+			name:    "case and spaces",
+			mission: "5   3\nROCK 2 2\n1  1 e ID=Scout-1 Type=Tracked ENERGY=40\nrfu[s?l:[o?r]]",
+			want:    "5 3\nrock 2 2\n1 1 E id=Scout-1 type=tracked energy=40\nRFU[S?L:[O?R]]",
+		},
+		{
+			name:    "comments",
+			mission: "# mission 42\n5 3\n#  rocks\nrock 1 1\n\n# first robot\n1 1 E\n# loops around\nRFRFRFRF\n\n# spare\n0 0 N\n\n  # the end  \n",
+			want:    "# mission 42\n5 3\n#  rocks\nrock 1 1\n# first robot\n1 1 E\n# loops around\nRFRFRFRF\n\n# spare\n0 0 N\n\n# the end",
+		},
+		{
+			name:    "unrecognized line",
+			mission: "5 3\n1 1 E\nR F\n",
+			wantErr: "line 3: expected a surface, a terrain, a robot position or instructions, got R F",
+		},
+		{
+			name:    "invalid mission",
+			mission: "5 3\n1 1 E\nRXF\n",
+			wantErr: "failed to load robots instructions",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb := NewMarsBuilder(nil)
+			got, err := mb.Format(strings.Split(tt.mission, "\n"))
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Fatalf("Format() got error %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Format() got error %v", err)
+			}
+			if strings.Join(got, "\n") != tt.want {
+				t.Errorf("Format() got\n%s\nwant\n%s", strings.Join(got, "\n"), tt.want)
+			}
+
+			again, err := mb.Format(got)
+			if err != nil || !reflect.DeepEqual(again, got) {
+				t.Errorf("Format() isn't idempotent, got\n%s", strings.Join(again, "\n"))
+			}
+		})
+	}
+}
+
+func TestMarsBuilder_Build_comments(t *testing.T) {
+	mb := NewMarsBuilder(nil)
+	m, err := mb.Build([]string{"# header", "5 3", "# robot", "1 1 E", "  # turning", "RF"})
+	if err != nil {
+		t.Fatalf("Build() got error %v", err)
+	}
+	if m.Surface.MaxX != 5 || len(m.Robots) != 1 || strings.Join(m.Robots[0].Instructions, "") != "RF" {
+		t.Errorf("Build() got %+v", m)
+	}
+
+	warnings, err := mb.Lint([]string{"# header", "5 3", "1 1 E", "# turning", "LR"})
+	if err != nil || len(warnings) == 0 || warnings[0].Line != 5 {
+		t.Errorf("Lint() got %v, %v, want a warning on line 5", warnings, err)
+	}
+}
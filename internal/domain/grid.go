@@ -0,0 +1,56 @@
+package domain
+
+import "strings"
+
+// Map returns the surface as ASCII art, one line per row from the top (MaxY) to the bottom (0)
+// operating robots are drawn as ^ > v < given their direction, robots not operating anymore as x,
+// scents as *, rocks as # and craters as o, empty grid points being drawn as .
+func (m *MarsExplorer) Map() []string {
+	grid := make([][]string, m.Surface.MaxY+1)
+	for y := range grid {
+		grid[y] = make([]string, m.Surface.MaxX+1)
+		for x := range grid[y] {
+			grid[y][x] = "."
+		}
+	}
+
+	draw := func(x, y int, s string) {
+		if m.Surface.Contains(x, y) {
+			grid[y][x] = s
+		}
+	}
+
+	for p, t := range m.Surface.Terrain {
+		switch t {
+		case TerrainRock:
+			draw(p.X, p.Y, "#")
+		case TerrainCrater:
+			draw(p.X, p.Y, "o")
+		}
+	}
+
+	for _, s := range m.Scents {
+		draw(s.PosX, s.PosY, "*")
+	}
+
+	for i := range m.Robots {
+		if r := &m.Robots[i]; r.Lost || r.Depleted {
+			draw(r.PosX, r.PosY, "x")
+		}
+	}
+
+	// operating robots are drawn last, over robots lost on the same grid point
+	arrows := map[string]string{DirectionNorth: "^", DirectionEast: ">", DirectionSouth: "v", DirectionWest: "<"}
+	for i := range m.Robots {
+		if r := &m.Robots[i]; !r.Lost && !r.Depleted {
+			draw(r.PosX, r.PosY, arrows[r.Direction])
+		}
+	}
+
+	lines := make([]string, 0, len(grid))
+	for y := len(grid) - 1; y >= 0; y-- {
+		lines = append(lines, strings.Join(grid[y], ""))
+	}
+
+	return lines
+}
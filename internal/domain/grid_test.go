@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarsExplorer_Map(t *testing.T) {
+	m := sampleMission()
+	m.Surface.Terrain = map[Point]string{{X: 4, Y: 0}: TerrainRock, {X: 5, Y: 1}: TerrainCrater}
+	m.SendInstructions()
+
+	want := []string{
+		"..vx..",
+		"......",
+		".>...o",
+		"....#.",
+	}
+	if got := m.Map(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() got %v, want %v", got, want)
+	}
+}
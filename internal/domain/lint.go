@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// LintRedundantTurns is a run of turns which can be written shorter, ie: "LR" or "RRR"
+	LintRedundantTurns = "W001"
+	// LintAfterLoss is instructions following the one making the robot lost or depleted, they are never run
+	LintAfterLoss = "W002"
+	// LintSameStart is a robot starting on the grid point another robot started on
+	LintSameStart = "W003"
+	// LintScentStart is a robot starting on a scent facing the edge, its forward moves being ignored until it turns
+	LintScentStart = "W004"
+	// LintUnreachable is a conditional branch which can't be taken, ie: the else branch of the inner
+	// conditional of "[O?[O?F:L]]", the sensor reading the same as when the outer conditional was evaluated
+	LintUnreachable = "W005"
+	// LintNeverMoves is a program which never moves the robot away from its starting grid point
+	LintNeverMoves = "W006"
+)
+
+// LintWarning is a suspicious part of a mission, which is valid nonetheless
+type LintWarning struct {
+	Code string
+	// Line is the line number within the mission, starting at 1
+	Line int
+	// Robot is the robot index within the mission
+	Robot   int
+	Label   string
+	Message string
+	// Fix is the suggested fix
+	Fix string
+}
+
+// String returns the warning as a single line, ie: "line 3: W001 robot #1: redundant turns LR (fix: remove them)"
+func (w LintWarning) String() string {
+	return fmt.Sprintf("line %d: %s robot %s: %s (fix: %s)", w.Line, w.Code, w.Label, w.Message, w.Fix)
+}
+
+// Lint reads a mission like Build and returns its suspicious parts, exploring a copy of it to find what will happen
+// the error is the one of Build when the mission can't be read
+func (mb *MarsBuilder) Lint(lines []string) ([]LintWarning, error) {
+	m, numbers, err := mb.build(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := make([]LintWarning, 0)
+	warn := func(ri int, line int, code, message, fix string) {
+		warnings = append(warnings, LintWarning{
+			Code:    code,
+			Line:    line,
+			Robot:   ri,
+			Label:   m.Robots[ri].Label(ri),
+			Message: message,
+			Fix:     fix,
+		})
+	}
+
+	for ri := range m.Robots {
+		r := &m.Robots[ri]
+		for _, w := range lintTurns(r.Instructions, r) {
+			warn(ri, numbers[ri].instructions, LintRedundantTurns, w[0], w[1])
+		}
+		for _, w := range lintBranches(r.Instructions) {
+			warn(ri, numbers[ri].instructions, LintUnreachable, w[0], w[1])
+		}
+
+		if !m.isRobotOffBound(*r) {
+			for before := 0; before < ri; before++ {
+				if b := m.Robots[before]; b.PosX == r.PosX && b.PosY == r.PosY {
+					warn(ri, numbers[ri].position, LintSameStart,
+						fmt.Sprintf("starts at %d %d like robot %s", r.PosX, r.PosY, b.Label(before)),
+						"start it on another grid point")
+					break
+				}
+			}
+		}
+	}
+
+	explored := m.Copy()
+	moves := &moveRecorder{moved: make([]bool, len(explored.Robots))}
+	explored.Observe(moves)
+	for ri := range explored.Robots {
+		r := &explored.Robots[ri]
+		if explored.isRobotOffBound(*r) {
+			continue
+		}
+
+		if x, y, ok := r.ahead(); ok && !explored.Surface.Contains(x, y) && explored.isThereARobotScent(*r, CommandForward) {
+			warn(ri, numbers[ri].position, LintScentStart,
+				fmt.Sprintf("starts on a scent at %d %d facing the edge, forward moves are ignored until it turns", r.PosX, r.PosY),
+				"turn it to face the surface")
+		}
+
+		for !r.Done() {
+			explored.step(ri)
+		}
+
+		if r.Loss != nil {
+			if after := r.Instructions[r.Loss.Instruction+1:]; len(after) > 0 {
+				state := "lost (" + r.Loss.Cause + ")"
+				if r.Depleted {
+					state = "depleted"
+				}
+				warn(ri, numbers[ri].instructions, LintAfterLoss,
+					fmt.Sprintf("%s at instruction %d, the %d instructions after it are never run", state, r.Loss.Instruction, len(after)),
+					fmt.Sprintf("remove %s", strings.Join(after, "")))
+			}
+		}
+
+		if len(r.Instructions) > 0 && !moves.moved[ri] {
+			if hasForward(r.Instructions) {
+				warn(ri, numbers[ri].instructions, LintNeverMoves,
+					fmt.Sprintf("never leaves %d %d, every forward move being ignored", m.Robots[ri].PosX, m.Robots[ri].PosY),
+					"check the scents and the terrain ahead of it")
+			} else {
+				warn(ri, numbers[ri].instructions, LintNeverMoves, "has no forward move", "add forward moves or remove the robot")
+			}
+		}
+	}
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		if warnings[i].Line != warnings[j].Line {
+			return warnings[i].Line < warnings[j].Line
+		}
+		return warnings[i].Code < warnings[j].Code
+	})
+
+	return warnings, nil
+}
+
+// lintTurns returns the runs of turns which can be written shorter as message and fix pairs,
+// conditional branches included
+func lintTurns(instructions []string, r *Robot) [][2]string {
+	var warnings [][2]string
+	flush := func(run []string, turns int) {
+		shortest := r.rotation(turns)
+		if len(run) <= len(shortest) {
+			return
+		}
+		fix := "remove them"
+		if len(shortest) > 0 {
+			fix = fmt.Sprintf("replace them with %s", strings.Join(shortest, ""))
+		}
+		warnings = append(warnings, [2]string{fmt.Sprintf("redundant turns %s", strings.Join(run, "")), fix})
+	}
+
+	var run []string
+	turns := 0
+	for _, c := range instructions {
+		if q, ok := quarterTurns[c]; ok {
+			run = append(run, c)
+			turns += q
+			continue
+		}
+
+		flush(run, turns)
+		run, turns = nil, 0
+
+		if !isConditional(c) {
+			continue
+		}
+		if cond, err := ParseConditional(c); err == nil {
+			warnings = append(warnings, lintTurns(cond.Then, r)...)
+			warnings = append(warnings, lintTurns(cond.Else, r)...)
+		}
+	}
+	flush(run, turns)
+
+	return warnings
+}
+
+// lintBranches returns the branches which can't be taken as message and fix pairs: a conditional starting
+// a branch reads the sensor of the enclosing conditional before anything happened, it always reads the same
+func lintBranches(instructions []string) [][2]string {
+	var warnings [][2]string
+	for _, c := range instructions {
+		if !isConditional(c) {
+			continue
+		}
+		cond, err := ParseConditional(c)
+		if err != nil {
+			continue
+		}
+
+		for i, branch := range [][]string{cond.Then, cond.Else} {
+			if len(branch) == 0 || !isConditional(branch[0]) {
+				continue
+			}
+			inner, err := ParseConditional(branch[0])
+			if err != nil || inner.Sensor != cond.Sensor {
+				continue
+			}
+
+			taken, unreachable, name := inner.Then, inner.Else, "else"
+			if i == 1 {
+				taken, unreachable, name = inner.Else, inner.Then, "then"
+			}
+			if len(unreachable) == 0 {
+				continue
+			}
+			warnings = append(warnings, [2]string{
+				fmt.Sprintf("the %s branch %s of %s is unreachable, the %s sensor reading the same as in %s", name, strings.Join(unreachable, ""), branch[0], cond.Sensor, c),
+				fmt.Sprintf("replace %s with %s", branch[0], strings.Join(taken, "")),
+			})
+		}
+
+		warnings = append(warnings, lintBranches(cond.Then)...)
+		warnings = append(warnings, lintBranches(cond.Else)...)
+	}
+
+	return warnings
+}
+
+// hasForward asserts the instructions contain a forward move, conditional branches included
+func hasForward(instructions []string) bool {
+	for _, c := range instructions {
+		if c == CommandForward {
+			return true
+		}
+		if !isConditional(c) {
+			continue
+		}
+		if cond, err := ParseConditional(c); err == nil && (hasForward(cond.Then) || hasForward(cond.Else)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// moveRecorder records which robots left their starting grid point
+type moveRecorder struct {
+	NopObserver
+	moved []bool
+}
+
+// Moved records a robot reaching another grid point
+func (m *moveRecorder) Moved(e MovedEvent) {
+	if e.From.X != e.To.X || e.From.Y != e.To.Y {
+		m.moved[e.Robot] = true
+	}
+}
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarsBuilder_Lint(t *testing.T) {
+	tests := []struct {
+		name    string
+		mission string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "clean mission",
+			mission: "5 3\n1 1 E\nRFRFRFRF\n",
+			want:    []string{},
+		},
+		{
+			name:    "redundant turns",
+			mission: "5 3\nrock 4 3\n\n1 1 E\nFLRFRRRF[O?LLLL:FLLL]\n",
+			want: []string{
+				"line 5: W001 robot #1: redundant turns LR (fix: remove them)",
+				"line 5: W001 robot #1: redundant turns RRR (fix: replace them with L)",
+				"line 5: W001 robot #1: redundant turns LLLL (fix: remove them)",
+				"line 5: W001 robot #1: redundant turns LLL (fix: replace them with R)",
+			},
+		},
+		{
+			name:    "u-turn shorter for a tracked robot only",
+			mission: "5 3\n1 1 E type=tracked\nFLLF\n2 2 E\nFLLF\n",
+			want:    []string{"line 3: W001 robot #1: redundant turns LL (fix: replace them with U)"},
+		},
+		{
+			name:    "instructions after a loss",
+			mission: "5 3\n3 2 N\nFRRFLLFFRRFLL\n",
+			want:    []string{"line 3: W002 robot #1: lost (edge) at instruction 7, the 5 instructions after it are never run (fix: remove RRFLL)"},
+		},
+		{
+			name:    "instructions after depletion",
+			mission: "5 3\n0 0 N energy=2\nFFR\n",
+			want:    []string{"line 3: W002 robot #1: depleted at instruction 1, the 1 instructions after it are never run (fix: remove R)"},
+		},
+		{
+			name:    "robots starting on the same grid point",
+			mission: "5 3\n1 1 E id=a\nF\n1 1 N id=b\nF\n9 9 N\nF\n9 9 N\nF\n",
+			want:    []string{"line 4: W003 robot b: starts at 1 1 like robot a (fix: start it on another grid point)"},
+		},
+		{
+			name:    "robot starting on a scent facing the edge",
+			mission: "5 3\n3 2 N\nFF\n\n3 3 N\nFLF\n4 3 E\nF\n",
+			want: []string{
+				"line 5: W004 robot #2: starts on a scent at 3 3 facing the edge, forward moves are ignored until it turns (fix: turn it to face the surface)",
+			},
+		},
+		{
+			name:    "unreachable branches",
+			mission: "5 3\n1 1 E\nF[S?[S?L:R]:[S?F:[B?L]]]\n",
+			want: []string{
+				"line 3: W005 robot #1: the else branch R of [S?L:R] is unreachable, the S sensor reading the same as in [S?[S?L:R]:[S?F:[B?L]]] (fix: replace [S?L:R] with L)",
+				"line 3: W005 robot #1: the then branch F of [S?F:[B?L]] is unreachable, the S sensor reading the same as in [S?[S?L:R]:[S?F:[B?L]]] (fix: replace [S?F:[B?L]] with [B?L])",
+			},
+		},
+		{
+			name:    "programs never moving",
+			mission: "5 3\nrock 2 1\n1 1 E\nLR\n1 2 S\nRLLR\n1 0 N\nFRF\n",
+			want: []string{
+				"line 4: W001 robot #1: redundant turns LR (fix: remove them)",
+				"line 4: W006 robot #1: has no forward move (fix: add forward moves or remove the robot)",
+				"line 6: W001 robot #2: redundant turns RLLR (fix: remove them)",
+				"line 6: W006 robot #2: has no forward move (fix: add forward moves or remove the robot)",
+			},
+		},
+		{
+			name:    "forward moves all ignored",
+			mission: "5 3\nrock 2 1\n1 1 E\nF\n",
+			want:    []string{"line 4: W006 robot #1: never leaves 1 1, every forward move being ignored (fix: check the scents and the terrain ahead of it)"},
+		},
+		{
+			name:    "line numbers with comments, terrain and robots without instructions",
+			mission: "# scouts\n5 3\n# rocks\nrock 4 3\n1 1 E\n\n# second scout\n2 2 N\n# turning\nFLRF\n",
+			want:    []string{"line 10: W001 robot #2: redundant turns LR (fix: remove them)"},
+		},
+		{
+			name:    "invalid mission",
+			mission: "5 3\n1 1 E\nFXF\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb := NewMarsBuilder(nil)
+			warnings, err := mb.Lint(strings.Split(tt.mission, "\n"))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Lint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := make([]string, 0, len(warnings))
+			for _, w := range warnings {
+				got = append(got, w.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lint() got\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Fields are the structured fields of a log entry, ie: the robot and its position
+type Fields map[string]interface{}
+
+// Logger is what MarsBuilder and MarsExplorer log to, see NewLogrusLogger, NewStdLogger and NopLogger
+type Logger interface {
+	// WithFields returns a Logger adding the fields to every entry
+	WithFields(fields Fields) Logger
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// logrusLogger logs to a logrus logger or entry
+type logrusLogger struct {
+	logger logrus.FieldLogger
+}
+
+// NewLogrusLogger returns a Logger logging to a *logrus.Logger or a *logrus.Entry, its level and format applying
+func NewLogrusLogger(logger logrus.FieldLogger) Logger {
+	return logrusLogger{logger: logger}
+}
+
+// WithFields returns a Logger adding the fields to every entry
+func (l logrusLogger) WithFields(fields Fields) Logger {
+	return logrusLogger{logger: l.logger.WithFields(logrus.Fields(fields))}
+}
+
+// Debugf logs at the debug level
+func (l logrusLogger) Debugf(format string, args ...interface{}) { l.logger.Debugf(format, args...) }
+
+// Infof logs at the info level
+func (l logrusLogger) Infof(format string, args ...interface{}) { l.logger.Infof(format, args...) }
+
+// Warnf logs at the warning level
+func (l logrusLogger) Warnf(format string, args ...interface{}) { l.logger.Warnf(format, args...) }
+
+// Errorf logs at the error level
+func (l logrusLogger) Errorf(format string, args ...interface{}) { l.logger.Errorf(format, args...) }
+
+// stdLogger logs to a standard library logger
+type stdLogger struct {
+	logger *log.Logger
+	debug  bool
+	fields Fields
+}
+
+// NewStdLogger returns a Logger logging to a standard library logger, debug entries being dropped unless debug is set
+// entries are written as "<LEVEL> <message> key=value...", fields being sorted by key
+func NewStdLogger(logger *log.Logger, debug bool) Logger {
+	return stdLogger{logger: logger, debug: debug}
+}
+
+// WithFields returns a Logger adding the fields to every entry
+func (l stdLogger) WithFields(fields Fields) Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return stdLogger{logger: l.logger, debug: l.debug, fields: merged}
+}
+
+// Debugf logs at the debug level, only when debug is set
+func (l stdLogger) Debugf(format string, args ...interface{}) {
+	if l.debug {
+		l.print("DEBUG", format, args)
+	}
+}
+
+// Infof logs at the info level
+func (l stdLogger) Infof(format string, args ...interface{}) { l.print("INFO", format, args) }
+
+// Warnf logs at the warning level
+func (l stdLogger) Warnf(format string, args ...interface{}) { l.print("WARN", format, args) }
+
+// Errorf logs at the error level
+func (l stdLogger) Errorf(format string, args ...interface{}) { l.print("ERROR", format, args) }
+
+// print writes an entry with its level and fields
+func (l stdLogger) print(level, format string, args []interface{}) {
+	var sb strings.Builder
+	sb.WriteString(level)
+	sb.WriteString(" ")
+	fmt.Fprintf(&sb, format, args...)
+
+	keys := make([]string, 0, len(l.fields))
+	for k := range l.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&sb, " %s=%v", k, l.fields[k])
+	}
+
+	l.logger.Print(sb.String())
+}
+
+// NopLogger drops every entry, it is the logger of a MarsExplorer unless set otherwise
+type NopLogger struct{}
+
+// WithFields returns the NopLogger
+func (NopLogger) WithFields(Fields) Logger { return NopLogger{} }
+
+// Debugf does nothing
+func (NopLogger) Debugf(string, ...interface{}) {}
+
+// Infof does nothing
+func (NopLogger) Infof(string, ...interface{}) {}
+
+// Warnf does nothing
+func (NopLogger) Warnf(string, ...interface{}) {}
+
+// Errorf does nothing
+func (NopLogger) Errorf(string, ...interface{}) {}
+
+// SetLogger sets the logger the simulation events are logged to, NopLogger when nil
+// copies of the MarsExplorer (see Copy) don't log, dry-runs and speculations being silent
+func (m *MarsExplorer) SetLogger(logger Logger) {
+	m.logger = logger
+}
+
+// log returns the logger of the MarsExplorer
+func (m *MarsExplorer) log() Logger {
+	if m.logger == nil {
+		return NopLogger{}
+	}
+
+	return m.logger
+}
+
+// logging asserts the simulation events are logged, the logger being set and not a NopLogger
+func (m *MarsExplorer) logging() bool {
+	if _, nop := m.logger.(NopLogger); nop {
+		return false
+	}
+
+	return m.logger != nil
+}
+
+// robotFields returns the fields identifying a robot and its position
+func (m *MarsExplorer) robotFields(ri int, p Pose) Fields {
+	return Fields{"robot": m.Robots[ri].Label(ri), "x": p.X, "y": p.Y, "direction": p.Direction}
+}
+
+// deployed logs a robot starting its instructions
+func (m *MarsExplorer) deployed(ri int) {
+	if !m.logging() || m.Robots[ri].Cursor > 0 {
+		return
+	}
+
+	fields := m.robotFields(ri, m.Robots[ri].Pose())
+	fields["instructions"] = len(m.Robots[ri].Instructions)
+	m.logger.WithFields(fields).Debugf("robot deployed")
+}
+
+// logEvent logs a simulation event at the debug level: ignored moves, scents left and robots lost or depleted,
+// moves not being logged
+// these are the expected outcomes of a mission, not anomalies, so they only show up once debugging
+func (m *MarsExplorer) logEvent(e Event) {
+	if !m.logging() {
+		return
+	}
+
+	switch e := e.(type) {
+	case BlockedEvent:
+		fields := m.robotFields(e.Robot, e.Pose)
+		fields["instruction"] = e.Instruction
+		fields["by"] = e.By
+		if e.By == BlockedByScent {
+			m.logger.WithFields(fields).Debugf("scent hit, forward move ignored")
+		} else {
+			m.logger.WithFields(fields).Debugf("forward move ignored")
+		}
+	case LostEvent:
+		fields := m.robotFields(e.Robot, e.Pose)
+		fields["instruction"] = e.Loss.Instruction
+		fields["cause"] = e.Loss.Cause
+		if e.Loss.Cause == LossCauseEnergy {
+			m.logger.WithFields(fields).Debugf("robot depleted")
+		} else {
+			m.logger.WithFields(fields).Debugf("robot lost")
+		}
+	case ScentLeftEvent:
+		m.logger.WithFields(m.robotFields(e.Robot, e.Pose)).Debugf("scent left")
+	}
+}
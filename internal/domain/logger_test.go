@@ -0,0 +1,136 @@
+package domain
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// recordingLogger records every entry as "<level> <message> <fields>"
+type recordingLogger struct {
+	fields  Fields
+	entries *[]string
+}
+
+func newRecordingLogger() recordingLogger {
+	return recordingLogger{entries: &[]string{}}
+}
+
+func (l recordingLogger) WithFields(fields Fields) Logger {
+	return recordingLogger{fields: fields, entries: l.entries}
+}
+
+func (l recordingLogger) Debugf(format string, args ...interface{}) { l.record("debug", format, args) }
+func (l recordingLogger) Infof(format string, args ...interface{})  { l.record("info", format, args) }
+func (l recordingLogger) Warnf(format string, args ...interface{})  { l.record("warn", format, args) }
+func (l recordingLogger) Errorf(format string, args ...interface{}) { l.record("error", format, args) }
+
+func (l recordingLogger) record(level, format string, args []interface{}) {
+	*l.entries = append(*l.entries, fmt.Sprintf("%s %s %v", level, fmt.Sprintf(format, args...), l.fields))
+}
+
+func TestMarsExplorer_SetLogger(t *testing.T) {
+	want := []string{
+		"debug robot deployed map[direction:E instructions:8 robot:#1 x:1 y:1]",
+		"debug robot deployed map[direction:N instructions:13 robot:#2 x:3 y:2]",
+		"debug robot lost map[cause:edge direction:N instruction:7 robot:#2 x:3 y:3]",
+		"debug scent left map[direction:N robot:#2 x:3 y:3]",
+		"debug robot deployed map[direction:W instructions:10 robot:#3 x:0 y:3]",
+		"debug scent hit, forward move ignored map[by:scent direction:N instruction:6 robot:#3 x:3 y:3]",
+	}
+
+	tests := []struct {
+		name string
+		send func(m *MarsExplorer)
+	}{
+		{name: "sequential", send: func(m *MarsExplorer) { m.SendInstructions() }},
+		{name: "parallel", send: func(m *MarsExplorer) { m.SendInstructionsParallel(2) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := sampleMission()
+			logger := newRecordingLogger()
+			m.SetLogger(logger)
+			tt.send(m)
+
+			if !reflect.DeepEqual(*logger.entries, want) {
+				t.Errorf("logged\n%s\nwant\n%s", strings.Join(*logger.entries, "\n"), strings.Join(want, "\n"))
+			}
+		})
+	}
+}
+
+func TestMarsExplorer_SetLogger_copy(t *testing.T) {
+	m := sampleMission()
+	logger := newRecordingLogger()
+	m.SetLogger(logger)
+
+	m.Analyze()
+	m.Copy().SendInstructions()
+	if len(*logger.entries) != 0 {
+		t.Errorf("copies logged %v, want nothing", *logger.entries)
+	}
+}
+
+func TestNewStdLogger(t *testing.T) {
+	tests := []struct {
+		name  string
+		debug bool
+		want  string
+	}{
+		{
+			name: "debug dropped",
+			want: "INFO robot #2 lost x=3 y=3\nWARN scent hit robot=scout x=3 y=3\n",
+		},
+		{
+			name:  "debug",
+			debug: true,
+			want:  "DEBUG deployed\nINFO robot #2 lost x=3 y=3\nWARN scent hit robot=scout x=3 y=3\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			l := NewStdLogger(log.New(&out, "", 0), tt.debug)
+
+			l.Debugf("deployed")
+			position := l.WithFields(Fields{"y": 3, "x": 3})
+			position.Infof("robot #%d lost", 2)
+			position.WithFields(Fields{"robot": "scout"}).Warnf("scent hit")
+
+			if out.String() != tt.want {
+				t.Errorf("logged %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewLogrusLogger(t *testing.T) {
+	var out bytes.Buffer
+	l := logrus.New()
+	l.SetOutput(&out)
+	l.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+
+	NewLogrusLogger(l).WithFields(Fields{"robot": "#1", "x": 1}).Warnf("robot %s", "lost")
+	NewLogrusLogger(l).Debugf("dropped below the logger level")
+
+	want := "level=warning msg=\"robot lost\" robot=\"#1\" x=1\n"
+	if out.String() != want {
+		t.Errorf("logged %q, want %q", out.String(), want)
+	}
+}
+
+func TestNopLogger(t *testing.T) {
+	var l Logger = NopLogger{}
+	l.WithFields(Fields{"robot": "#1"}).Errorf("dropped")
+
+	m := sampleMission()
+	m.SetLogger(l)
+	if m.logging() {
+		t.Errorf("logging() got true with a NopLogger")
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+const (
+	// LossCauseEdge the robot moved off the edge of the grid
+	LossCauseEdge = "edge"
+	// LossCauseCrater the robot fell into a crater
+	LossCauseCrater = "crater"
+	// LossCauseCollision the robot ran into another one, defined for the report consumers
+	// but not produced by the simulation yet
+	LossCauseCollision = "collision"
+	// LossCauseEnergy the robot couldn't afford its next command, it is reported as depleted rather than lost
+	LossCauseEnergy = "energy"
+)
+
+// Loss is the record of a robot which stopped operating
+type Loss struct {
+	Cause string `json:"cause"`
+	// Instruction is the index within the robot instructions, a conditional counting as a single instruction
+	Instruction int `json:"instruction"`
+	// Target is the grid point the robot tried to reach, off the grid when lost over the edge
+	Target Point `json:"target"`
+}
@@ -1,31 +1,44 @@
 package domain
 
 import (
+	"context"
 	"fmt"
-	"github.com/sirupsen/logrus"
 	"strconv"
 	"strings"
 )
 
+// MaxInstructions is the maximum length of a robot instruction line, unless set otherwise (see MarsBuilder.SetLimits)
+const MaxInstructions = 100
+
+// MaxCoordinate is the maximum value for any coordinate of the grid, unless set otherwise (see MarsBuilder.SetLimits)
+const MaxCoordinate = 50
+
 // Explorer is our main interface (only implemented by MarsExplorer for now)
 type Explorer interface {
 	SendInstructions()
 }
 
-// MarsBuilder allows us to override / provide a *logrus.logger (should have a particular interface)
+// MarsBuilder allows us to override / provide a Logger, the missions built logging their simulation events to it
 type MarsBuilder struct {
-	logger *logrus.Logger
+	logger          Logger
+	energyCosts     EnergyCosts
+	maxCoordinate   int
+	maxInstructions int
+	rules           Rules
 }
 
 // Surface is the representation of Mars as a grid
 type Surface struct {
 	MaxX, MaxY int
+	// Terrain holds the grid points which aren't flat (see TerrainRock and TerrainCrater)
+	Terrain map[Point]string
 }
 
 // Scent is the representation of the trace of a robot which got lost
 type Scent struct {
-	posX, posY int
-	direction  string
+	PosX, PosY int
+	// Direction is the direction of the fatal move
+	Direction string
 }
 
 // MarsExplorer contains all the pieces to execute the instructions to the robots
@@ -33,66 +46,227 @@ type MarsExplorer struct {
 	Surface *Surface
 	Robots  []Robot
 	Scents  []Scent
+	// Rules are the policies of the mission, the default ones when zero
+	Rules Rules
+	// MaxInstructions is the maximum length of the instructions piloted to a robot, MaxInstructions when 0 (see Pilot)
+	MaxInstructions int
+
+	observers []Observer
+	logger    Logger
+}
+
+// NewMarsBuilder is our MarsBuilder constructor, allows us to get started by injecting a logger (NopLogger when nil)
+func NewMarsBuilder(logger Logger) MarsBuilder {
+	if logger == nil {
+		logger = NopLogger{}
+	}
+
+	return MarsBuilder{logger: logger, energyCosts: DefaultEnergyCosts}
 }
 
-// NewMarsBuilder is our MarsBuilder constructor, allows us to get started by injecting a logger
-func NewMarsBuilder(logger *logrus.Logger) MarsBuilder {
-	return MarsBuilder{logger: logger}
+// SetLimits overrides the maximum value for any coordinate of the grid and the maximum length of an instruction line
+// a limit <= 0 meaning the default one (MaxCoordinate and MaxInstructions)
+func (mb *MarsBuilder) SetLimits(maxCoordinate, maxInstructions int) {
+	mb.maxCoordinate = maxCoordinate
+	mb.maxInstructions = maxInstructions
+}
+
+// coordinateLimit returns the maximum value for any coordinate of the grid, MaxCoordinate unless set
+func (mb *MarsBuilder) coordinateLimit() int {
+	if mb.maxCoordinate <= 0 {
+		return MaxCoordinate
+	}
+
+	return mb.maxCoordinate
+}
+
+// instructionsLimit returns the maximum length of an instruction line, MaxInstructions unless set
+func (mb *MarsBuilder) instructionsLimit() int {
+	if mb.maxInstructions <= 0 {
+		return MaxInstructions
+	}
+
+	return mb.maxInstructions
+}
+
+// SetRules sets the policies of the missions built
+func (mb *MarsBuilder) SetRules(rules Rules) {
+	mb.rules = rules
+}
+
+// SetEnergyCosts overrides the energy drained by each command for robots declaring an energy budget
+func (mb *MarsBuilder) SetEnergyCosts(costs EnergyCosts) {
+	mb.energyCosts = costs
 }
 
 // Build is setting up our MarsExplorer
-// by receiving a specific array of instructions to setup both our surface and robots
+// by receiving a specific array of instructions to setup both our surface and robots, comment lines being ignored
 func (mb *MarsBuilder) Build(instructions []string) (*MarsExplorer, error) {
+	m, _, err := mb.build(instructions)
+
+	return m, err
+}
+
+// build is Build also returning the line numbers of every robot within the mission, as read while parsing
+func (mb *MarsBuilder) build(instructions []string) (*MarsExplorer, []missionLines, error) {
+	instructions, numbers := stripComments(instructions)
 	if len(instructions) == 0 {
-		return nil, fmt.Errorf("expected instructions, received %d", len(instructions))
+		return nil, nil, fmt.Errorf("expected instructions, received %d", len(instructions))
 	}
 
 	surface, err := mb.NewSurface(instructions[0])
 	if err != nil {
-		return nil, fmt.Errorf("failed to build mars surface, got %q", err)
+		return nil, nil, fmt.Errorf("failed to build mars surface, got %q", err)
+	}
+
+	remaining, err := mb.loadTerrain(surface, instructions[1:])
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load mars terrain, got %q", err)
+	}
+	lines := make([]string, 0, len(remaining))
+	lineNumbers := make([]int, 0, len(remaining))
+	for _, i := range remaining {
+		lines = append(lines, instructions[1+i])
+		lineNumbers = append(lineNumbers, numbers[1+i])
 	}
 
-	robots, err := mb.LoadRobotInstructions(instructions[1:])
+	robots, robotLines, err := mb.loadRobots(lines, lineNumbers)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load robots instructions, got %q", err)
+		return nil, nil, fmt.Errorf("failed to load robots instructions, got %q", err)
 	}
 
 	return &MarsExplorer{
-		Surface: surface,
-		Robots:  robots,
-	}, nil
+		Surface:         surface,
+		Robots:          robots,
+		Rules:           mb.rules,
+		MaxInstructions: mb.maxInstructions,
+		logger:          mb.logger,
+	}, robotLines, nil
 }
 
 // SendInstructions is the main entry point to iterate through our robots and execute the given instructions
+// robots carry on from their Cursor, a mission can then be resumed (see Restore)
 func (m *MarsExplorer) SendInstructions() {
-	for r := range m.Robots {
-		if m.isRobotOffBound(m.Robots[r]) {
-			// panic / err?
-			// continue for now
-			continue
-		}
+	_, _ = m.SendInstructionsContext(context.Background(), 0)
+}
+
+// step executes the next instruction of the robot ri (its index within Robots) and moves its cursor forward
+// it returns false as soon as the robot is not operating anymore (ie: lost)
+func (m *MarsExplorer) step(ri int) bool {
+	r := &m.Robots[ri]
+	index := r.Cursor
+	r.Cursor++
 
-		for i := range m.Robots[r].Instructions {
-			if m.isThereARobotScent(m.Robots[r], m.Robots[r].Instructions[i]) {
+	return m.run(ri, r.Instructions[index:index+1], index)
+}
+
+// run executes a sequence of instructions on a robot, conditionals being evaluated against the live surface state
+// index is the position of the instruction being run within the robot instructions
+// it returns false as soon as the robot is not operating anymore (ie: lost)
+func (m *MarsExplorer) run(ri int, instructions []string, index int) bool {
+	for _, c := range instructions {
+		if isConditional(c) {
+			cond, err := ParseConditional(c)
+			if err != nil {
+				// instructions are validated on load, ignore it like any unsupported command
 				continue
 			}
 
-			// @TODO check for error
-			_ = m.Robots[r].Execute(m.Robots[r].Instructions[i])
+			if !m.run(ri, cond.branch(m.sense(&m.Robots[ri], cond.Sensor)), index) {
+				return false
+			}
+			continue
+		}
 
-			if m.isRobotOffBound(m.Robots[r]) {
-				m.Robots[r].lost()
-				m.leaveScent(m.Robots[r])
-				break
+		if !m.execute(ri, c, index) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// execute runs a single command on a robot, taking care of scents, terrain and edges of the surface
+// a forward command is executed one grid point at a time given the robot speed
+// it returns false as soon as the robot is not operating anymore (ie: lost)
+func (m *MarsExplorer) execute(ri int, c string, index int) bool {
+	r := &m.Robots[ri]
+	steps := 1
+	if c == CommandForward {
+		steps = r.Capabilities().Speed
+	}
+
+	for s := 0; s < steps; s++ {
+		if m.isThereARobotScent(*r, c) {
+			m.notify(BlockedEvent{Robot: ri, ID: r.ID, Instruction: index, Command: c, Pose: r.Pose(), By: BlockedByScent})
+			return true
+		}
+
+		target := Point{X: r.PosX, Y: r.PosY}
+		effect := TerrainPass
+		if c == CommandForward {
+			if x, y, ok := r.ahead(); ok {
+				target = Point{X: x, Y: y}
+				effect = m.terrainEffect(r, x, y)
 			}
 		}
+		if effect == TerrainBlock {
+			m.notify(BlockedEvent{Robot: ri, ID: r.ID, Instruction: index, Command: c, Pose: r.Pose(), By: BlockedByTerrain})
+			return true
+		}
+		if c == CommandForward && m.Rules.Edge == EdgeStop && !m.Surface.Contains(target.X, target.Y) {
+			m.notify(BlockedEvent{Robot: ri, ID: r.ID, Instruction: index, Command: c, Pose: r.Pose(), By: BlockedByEdge})
+			return true
+		}
+
+		from := r.Pose()
+		if err := r.Execute(c); err == nil {
+			m.notify(MovedEvent{Robot: ri, ID: r.ID, Instruction: index, Command: c, Step: s, From: from, To: r.Pose()})
+		}
+
+		if r.Depleted {
+			r.Loss = &Loss{Cause: LossCauseEnergy, Instruction: index, Target: target}
+			m.notify(LostEvent{Robot: ri, ID: r.ID, Pose: r.Pose(), Loss: *r.Loss})
+			return false
+		}
+
+		if m.isRobotOffBound(*r) {
+			m.lose(ri, &Loss{Cause: LossCauseEdge, Instruction: index, Target: target})
+			return false
+		}
+
+		if effect == TerrainFall {
+			m.lose(ri, &Loss{Cause: LossCauseCrater, Instruction: index, Target: target})
+			return false
+		}
 	}
+
+	return true
+}
+
+// lose marks a robot as lost, leaving a scent behind unless scents are off
+func (m *MarsExplorer) lose(ri int, loss *Loss) {
+	r := &m.Robots[ri]
+	r.lost(loss)
+	m.notify(LostEvent{Robot: ri, ID: r.ID, Pose: r.Pose(), Loss: *loss})
+
+	if m.Rules.Scent == ScentOff {
+		return
+	}
+
+	m.leaveScent(*r)
+	m.notify(ScentLeftEvent{Robot: ri, ID: r.ID, Pose: r.Pose()})
+}
+
+// Contains asserts a grid point is part of the surface
+func (s *Surface) Contains(x, y int) bool {
+	return x >= 0 && y >= 0 && x <= s.MaxX && y <= s.MaxY
 }
 
 // NewSurface is the Surface constructor making sure the grid is in order
 // The first line of input is the upper-right coordinates of the rectangular world, the lower-left
 // coordinates are assumed to be 0, 0.
-// The maximum value for any coordinate is 50.
+// The maximum value for any coordinate is 50 (see SetLimits).
 func (mb *MarsBuilder) NewSurface(line string) (*Surface, error) {
 	l := strings.Split(line, " ")
 
@@ -113,9 +287,9 @@ func (mb *MarsBuilder) NewSurface(line string) (*Surface, error) {
 		return nil, err
 	}
 
-	if maxX > 50 || maxY > 50 {
-		mb.logger.Errorf("maximum value for the grid execeeded 50")
-		return nil, fmt.Errorf("maximum value for the grid execeeded 50")
+	if limit := mb.coordinateLimit(); maxX > limit || maxY > limit {
+		mb.logger.Errorf("maximum value for the grid execeeded %d", limit)
+		return nil, fmt.Errorf("maximum value for the grid execeeded %d", limit)
 	}
 
 	return &Surface{
@@ -128,79 +302,182 @@ func (mb *MarsBuilder) NewSurface(line string) (*Surface, error) {
 // It consists of a sequence of robot positions and instructions (two lines per
 // robot). A position consists of two integers specifying the initial coordinates of the robot and
 // an orientation (N, S, E, W), all separated by whitespace on one line. A robot instruction is a
-// string of the letters “L”, “R”, and “F” on one line.
-// All instruction strings will be less than 100 characters in length.
+// string of the letters “L”, “R”, and “F” on one line, optionally mixed with conditionals (see Conditional).
+// An instruction line belongs to the robot positioned right before it, a robot without one having no instructions.
+// A position can be followed by options written as key=value, ie: "1 1 E id=scout-1 energy=40 type=hover" (see robotOption).
+// All instruction strings will be less than 100 characters in length (see SetLimits).
 func (mb *MarsBuilder) LoadRobotInstructions(lines []string) ([]Robot, error) {
+	robots, _, err := mb.loadRobots(lines, nil)
+
+	return robots, err
+}
+
+// missionLines are the line numbers of a robot within a mission, 0 when missing
+type missionLines struct {
+	position, instructions int
+}
+
+// loadRobots is LoadRobotInstructions also returning the line numbers of every robot,
+// numbers being the line number of each line within the mission (its index + 1 when nil)
+func (mb *MarsBuilder) loadRobots(lines []string, numbers []int) ([]Robot, []missionLines, error) {
 	if len(lines) == 0 {
-		return nil, fmt.Errorf("expected instructions got 0")
+		return nil, nil, fmt.Errorf("expected instructions got 0")
+	}
+
+	number := func(i int) int {
+		if numbers == nil {
+			return i + 1
+		}
+		return numbers[i]
 	}
 
 	robots := make([]Robot, 0)
-	rCount := 0
-	for _, v := range lines {
+	robotLines := make([]missionLines, 0)
+	ids := make(map[string]struct{})
+	for i, v := range lines {
 		if v == "" {
 			continue
 		}
 		l := strings.Split(v, " ")
-		switch len(l) {
-		case 3:
-			var posX int
-			posX, err := strconv.Atoi(l[0])
+		switch {
+		case len(l) >= 3:
+			robot := Robot{Direction: l[2]} // @TODO validate direction or maybe on execution
+			label := robotLabel(l[3:], len(robots))
+			var err error
+			robot.PosX, err = strconv.Atoi(l[0])
 			if err != nil {
-				mb.logger.Errorf(`failed to convert pos X "%s" into integer, got %q`, l[0], err)
-				return nil, err
+				mb.logger.WithFields(Fields{"robot": label}).Errorf(`failed to convert pos X "%s" into integer, got %q`, l[0], err)
+				return nil, nil, fmt.Errorf("robot %s: %s", label, err)
 			}
-			var posY int
-			posY, err = strconv.Atoi(l[1])
+			robot.PosY, err = strconv.Atoi(l[1])
 			if err != nil {
-				mb.logger.Errorf(`failed to convert pos Y "%s" into integer, got %q`, l[0], err)
-				return nil, err
+				mb.logger.WithFields(Fields{"robot": label}).Errorf(`failed to convert pos Y "%s" into integer, got %q`, l[1], err)
+				return nil, nil, fmt.Errorf("robot %s: %s", label, err)
 			}
-			robots = append(robots, Robot{
-				PosX:      posX,
-				PosY:      posY,
-				Direction: l[2], // @TODO validate direction or maybe on execution
-			})
+			for _, o := range l[3:] {
+				if err := mb.robotOption(&robot, o); err != nil {
+					mb.logger.WithFields(Fields{"robot": label}).Errorf(`failed to read robot option "%s", got %q`, o, err)
+					return nil, nil, fmt.Errorf("robot %s: %s", label, err)
+				}
+			}
+			if robot.ID != "" {
+				if _, ok := ids[robot.ID]; ok {
+					mb.logger.WithFields(Fields{"robot": label}).Errorf(`duplicate robot id "%s"`, robot.ID)
+					return nil, nil, fmt.Errorf("robot %s: duplicate robot id %s", label, robot.ID)
+				}
+				ids[robot.ID] = struct{}{}
+			}
+			robots = append(robots, robot)
+			robotLines = append(robotLines, missionLines{position: number(i)})
 			continue
-		case 1:
-			// @TODO validate instructions  or maybe on execution
-			robots[rCount].Instructions = strings.SplitAfter(v, "")
-			if len(robots[rCount].Instructions) > 100 {
-				mb.logger.Errorf("instructions are limited to 100")
-				return nil, fmt.Errorf("instructions are limited to 100")
+		case len(l) == 1:
+			// instructions belong to the last positioned robot, a robot without instructions having none
+			rCount := len(robots) - 1
+			if rCount < 0 || robots[rCount].Instructions != nil {
+				// labelled as the robot whose position is missing
+				label := robotLabel(nil, len(robots))
+				mb.logger.WithFields(Fields{"robot": label}).Errorf(`instructions "%s" without a robot position`, v)
+				return nil, nil, fmt.Errorf("robot %s: instructions %s without a robot position", label, v)
+			}
+			label := robots[rCount].Label(rCount)
+			if limit := mb.instructionsLimit(); len(v) > limit {
+				mb.logger.WithFields(Fields{"robot": label}).Errorf("instructions are limited to %d", limit)
+				return nil, nil, fmt.Errorf("robot %s: instructions are limited to %d", label, limit)
+			}
+			instructions, err := TokenizeInstructions(v)
+			if err != nil {
+				mb.logger.WithFields(Fields{"robot": label}).Errorf(`failed to read instructions "%s", got %q`, v, err)
+				return nil, nil, fmt.Errorf("robot %s: %s", label, err)
+			}
+			robots[rCount].Instructions = instructions
+			robotLines[rCount].instructions = number(i)
+			if err := robots[rCount].ValidateInstructions(); err != nil {
+				mb.logger.WithFields(Fields{"robot": label}).Errorf(`invalid instructions "%s", got %q`, v, err)
+				return nil, nil, fmt.Errorf("robot %s: %s", label, err)
 			}
-			rCount++
 			continue
 		default:
 			continue
 		}
 	}
 
-	return robots, nil
+	return robots, robotLines, nil
 }
 
-// isRobotOffBound asserts a robot is still on the planet
-func (m *MarsExplorer) isRobotOffBound(r Robot) bool {
-	if r.PosY > m.Surface.MaxY {
-		return true
+// robotLabel names a robot being read from its position line options, before it's fully parsed
+// the id option is used when given, otherwise the robot's rank in the mission (see Robot.Label)
+func robotLabel(options []string, index int) string {
+	var r Robot
+	for _, o := range options {
+		if strings.HasPrefix(o, "id=") {
+			r.ID = strings.TrimPrefix(o, "id=")
+		}
 	}
 
-	if r.PosX > m.Surface.MaxX {
-		return true
+	return r.Label(index)
+}
+
+// robotOption applies a key=value option given on a robot position line
+// energy=<n> gives the robot a battery holding n units of energy
+// type=<type> sets the robot type, one of RobotTypes
+// id=<id> names the robot, ids being unique within a mission
+func (mb *MarsBuilder) robotOption(r *Robot, option string) error {
+	kv := strings.SplitN(option, "=", 2)
+	if len(kv) != 2 {
+		return fmt.Errorf("expected option as key=value, got %s", option)
 	}
 
-	return false
+	switch kv[0] {
+	case "energy":
+		level, err := strconv.Atoi(kv[1])
+		if err != nil {
+			return fmt.Errorf("failed to convert energy %s into integer, got %q", kv[1], err)
+		}
+		if level < 0 {
+			return fmt.Errorf("energy can't be negative, got %d", level)
+		}
+		r.Battery = &Battery{Level: level, Costs: mb.energyCosts}
+		return nil
+	case "type":
+		if _, ok := RobotTypes[kv[1]]; !ok {
+			return fmt.Errorf("unsupported robot type %s", kv[1])
+		}
+		r.Type = kv[1]
+		return nil
+	case "id":
+		if kv[1] == "" {
+			return fmt.Errorf("robot id can't be empty")
+		}
+		r.ID = kv[1]
+		return nil
+	default:
+		return fmt.Errorf("unsupported option %s", kv[0])
+	}
+}
+
+// isRobotOffBound asserts a robot is still on the planet
+func (m *MarsExplorer) isRobotOffBound(r Robot) bool {
+	return !m.Surface.Contains(r.PosX, r.PosY)
 }
 
 // isThereARobotScent verify if there isn't a robot's scent left for that grid position
+// with ScentCell, only a forward move making the robot lost is guarded by a scent
 func (m *MarsExplorer) isThereARobotScent(r Robot, c string) bool {
-	if len(m.Scents) == 0 {
+	if len(m.Scents) == 0 || c != CommandForward {
 		return false
 	}
 
+	if m.Rules.Scent == ScentCell {
+		x, y, ok := r.ahead()
+		if !ok || (m.Surface.Contains(x, y) && m.terrainEffect(&r, x, y) != TerrainFall) {
+			return false
+		}
+	}
+
+	key := m.Rules.scentKey(r.PosX, r.PosY, r.Direction)
 	for _, s := range m.Scents {
 		// sounds like there might a better way to do this
-		if s.posY == r.PosY && s.posX == r.PosX && s.direction == r.Direction && c == CommandForward {
+		if m.Rules.scentKey(s.PosX, s.PosY, s.Direction) == key {
 			return true
 		}
 	}
@@ -211,8 +488,8 @@ func (m *MarsExplorer) isThereARobotScent(r Robot, c string) bool {
 // leaveScent create a new scent when a robot got lost
 func (m *MarsExplorer) leaveScent(r Robot) {
 	m.Scents = append(m.Scents, Scent{
-		posX:      r.PosX,
-		posY:      r.PosY,
-		direction: r.Direction,
+		PosX:      r.PosX,
+		PosY:      r.PosY,
+		Direction: r.Direction,
 	})
 }
@@ -96,7 +96,7 @@ func TestMarsBuilder_NewSurface(t *testing.T) {
 			l.SetOutput(ioutil.Discard)
 
 			mb := &MarsBuilder{
-				logger: l,
+				logger: NewLogrusLogger(l),
 			}
 			got, err := mb.NewSurface(tt.args.line)
 			if (err != nil) != tt.wantErr {
@@ -157,6 +157,194 @@ func TestMarsBuilder_LoadRobotInstructions(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "robot without instructions",
+			args: args{
+				lines: []string{
+					"1 1 E",
+					"",
+					"1 2 N",
+					"RF",
+					"",
+				},
+			},
+			want: []Robot{
+				{
+					PosX:      1,
+					PosY:      1,
+					Direction: "E",
+				},
+				{
+					PosX:         1,
+					PosY:         2,
+					Direction:    "N",
+					Instructions: []string{"R", "F"},
+				},
+			},
+		},
+		{
+			name: "instructions without a robot position",
+			args: args{
+				lines: []string{
+					"RF",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "successfully load conditional instructions",
+			args: args{
+				lines: []string{
+					"1 1 E",
+					"F[O?R:F]F",
+				},
+			},
+			want: []Robot{
+				{
+					PosX:         1,
+					PosY:         1,
+					Direction:    "E",
+					Instructions: []string{"F", "[O?R:F]", "F"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "successfully load robots with an energy budget",
+			args: args{
+				lines: []string{
+					"1 1 E energy=40",
+					"RF",
+				},
+			},
+			want: []Robot{
+				{
+					PosX:         1,
+					PosY:         1,
+					Direction:    "E",
+					Instructions: []string{"R", "F"},
+					Battery:      &Battery{Level: 40},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "successfully load typed robots",
+			args: args{
+				lines: []string{
+					"1 1 E type=tracked",
+					"RFU",
+				},
+			},
+			want: []Robot{
+				{
+					Type:         RobotTypeTracked,
+					PosX:         1,
+					PosY:         1,
+					Direction:    "E",
+					Instructions: []string{"R", "F", "U"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "successfully load robots with ids",
+			args: args{
+				lines: []string{
+					"1 1 E id=scout-1",
+					"RF",
+					"2 2 N",
+					"F",
+					"3 3 S id=scout-2",
+					"L",
+				},
+			},
+			want: []Robot{
+				{ID: "scout-1", PosX: 1, PosY: 1, Direction: "E", Instructions: []string{"R", "F"}},
+				{PosX: 2, PosY: 2, Direction: "N", Instructions: []string{"F"}},
+				{ID: "scout-2", PosX: 3, PosY: 3, Direction: "S", Instructions: []string{"L"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate robot ids",
+			args: args{
+				lines: []string{
+					"1 1 E id=scout-1",
+					"RF",
+					"2 2 N id=scout-1",
+					"F",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "empty robot id",
+			args: args{
+				lines: []string{
+					"1 1 E id=",
+					"RF",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "unsupported robot type",
+			args: args{
+				lines: []string{
+					"1 1 E type=legged",
+					"RF",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "command unsupported by the robot type",
+			args: args{
+				lines: []string{
+					"1 1 E type=hover",
+					"RFU",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "unsupported robot option",
+			args: args{
+				lines: []string{
+					"1 1 E fuel=40",
+					"RF",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "invalid energy budget",
+			args: args{
+				lines: []string{
+					"1 1 E energy=-1",
+					"RF",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "malformed conditional instructions",
+			args: args{
+				lines: []string{
+					"1 1 E",
+					"F[O?R:F",
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "instructions can't exceed a 100",
 			args: args{
@@ -182,7 +370,7 @@ func TestMarsBuilder_LoadRobotInstructions(t *testing.T) {
 			l.SetOutput(ioutil.Discard)
 
 			mb := &MarsBuilder{
-				logger: l,
+				logger: NewLogrusLogger(l),
 			}
 			got, err := mb.LoadRobotInstructions(tt.args.lines)
 			if (err != nil) != tt.wantErr {
@@ -196,6 +384,54 @@ func TestMarsBuilder_LoadRobotInstructions(t *testing.T) {
 	}
 }
 
+func TestMarsBuilder_LoadRobotInstructionsErrorLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  string
+	}{
+		{
+			name:  "invalid position",
+			lines: []string{"1 1 E", "F", "x 2 N id=scout-2"},
+			want:  "robot scout-2: ",
+		},
+		{
+			name:  "unsupported robot option",
+			lines: []string{"1 1 E fuel=40 id=scout-1"},
+			want:  "robot scout-1: ",
+		},
+		{
+			name:  "duplicate robot ids",
+			lines: []string{"1 1 E id=scout-1", "2 2 N id=scout-1"},
+			want:  "robot scout-1: ",
+		},
+		{
+			name:  "instructions limit",
+			lines: []string{"1 1 E", "F", "2 2 N", strings.Repeat("F", 101)},
+			want:  "robot #2: ",
+		},
+		{
+			name:  "malformed instructions",
+			lines: []string{"1 1 E id=scout-1", "F[O?R"},
+			want:  "robot scout-1: ",
+		},
+		{
+			name:  "instructions without a robot position",
+			lines: []string{"1 1 E", "F", "R"},
+			want:  "robot #2: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb := NewMarsBuilder(nil)
+			_, err := mb.LoadRobotInstructions(tt.lines)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("LoadRobotInstructions() error = %v, want prefix %q", err, tt.want)
+			}
+		})
+	}
+}
+
 func TestMarsExplorer_SendInstructions(t *testing.T) {
 	type fields struct {
 		Surface *Surface
@@ -218,7 +454,7 @@ func TestMarsExplorer_SendInstructions(t *testing.T) {
 				},
 			},
 			want: []Robot{
-				{PosX: 2, PosY: 1, Direction: "E", Instructions: []string{"F", "L", "F", "R", "F"}},
+				{PosX: 2, PosY: 1, Direction: "E", Instructions: []string{"F", "L", "F", "R", "F"}, Cursor: 5},
 			},
 		},
 
@@ -234,7 +470,7 @@ func TestMarsExplorer_SendInstructions(t *testing.T) {
 				},
 			},
 			want: []Robot{
-				{PosX: 3, PosY: 3, Direction: "N", Lost: true, Instructions: []string{"F", "R", "R", "F", "L", "L", "F", "F", "R", "R", "F", "L", "L"}},
+				{PosX: 3, PosY: 3, Direction: "N", Lost: true, Loss: &Loss{Cause: LossCauseEdge, Instruction: 7, Target: Point{X: 3, Y: 4}}, Instructions: []string{"F", "R", "R", "F", "L", "L", "F", "F", "R", "R", "F", "L", "L"}, Cursor: 8},
 			},
 		},
 		{
@@ -250,8 +486,25 @@ func TestMarsExplorer_SendInstructions(t *testing.T) {
 				},
 			},
 			want: []Robot{
-				{PosX: 3, PosY: 3, Direction: "N", Lost: true, Instructions: []string{"F", "R", "R", "F", "L", "L", "F", "F", "R", "R", "F", "L", "L"}},
-				{PosX: 4, PosY: 3, Direction: "E", Lost: false, Instructions: []string{"F", "R", "F"}},
+				{PosX: 3, PosY: 3, Direction: "N", Lost: true, Loss: &Loss{Cause: LossCauseEdge, Instruction: 7, Target: Point{X: 3, Y: 4}}, Instructions: []string{"F", "R", "R", "F", "L", "L", "F", "F", "R", "R", "F", "L", "L"}, Cursor: 8},
+				{PosX: 4, PosY: 3, Direction: "E", Lost: false, Instructions: []string{"F", "R", "F"}, Cursor: 3},
+			},
+		},
+		{
+			name: "robot can get lost over the lower edges",
+			fields: fields{
+				Surface: &Surface{
+					MaxX: 5,
+					MaxY: 3,
+				},
+				Robots: []Robot{
+					{PosX: 0, PosY: 1, Direction: "S", Instructions: []string{"F", "F", "R"}},
+					{PosX: 0, PosY: 2, Direction: "W", Instructions: []string{"F", "L"}},
+				},
+			},
+			want: []Robot{
+				{PosX: 0, PosY: 0, Direction: "S", Lost: true, Loss: &Loss{Cause: LossCauseEdge, Instruction: 1, Target: Point{X: 0, Y: -1}}, Instructions: []string{"F", "F", "R"}, Cursor: 2},
+				{PosX: 0, PosY: 2, Direction: "W", Lost: true, Loss: &Loss{Cause: LossCauseEdge, Instruction: 0, Target: Point{X: -1, Y: 2}}, Instructions: []string{"F", "L"}, Cursor: 1},
 			},
 		},
 	}
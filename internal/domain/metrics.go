@@ -0,0 +1,216 @@
+package domain
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultDurationBuckets are the upper bounds, in seconds, of the simulation duration histogram buckets
+var DefaultDurationBuckets = []float64{0.0001, 0.001, 0.01, 0.1, 1, 10}
+
+// Metrics collects the statistics of the missions explored through Explore, it is safe for concurrent use
+// there is no global registry, every Metrics holding its own statistics
+type Metrics struct {
+	mu    sync.Mutex
+	stats MissionStats
+	// now is the clock measuring the simulation duration
+	now func() time.Time
+}
+
+// MissionStats are the statistics of the missions explored
+type MissionStats struct {
+	MissionsRun int `json:"missions_run"`
+	// RobotsDeployed is the number of robots starting their instructions, robots starting off the grid excluded
+	RobotsDeployed int `json:"robots_deployed"`
+	// RobotsLost is the number of robots lost per Loss.Cause, depleted robots included
+	RobotsLost map[string]int `json:"robots_lost"`
+	// ScentSaves is the number of forward moves ignored because of a scent
+	ScentSaves int `json:"scent_saves"`
+	// Commands is the number of commands executed per command, ignored moves excluded
+	Commands map[string]int `json:"commands"`
+	// Duration is the histogram of the simulation duration of the missions, in seconds
+	Duration Histogram `json:"simulation_duration_seconds"`
+}
+
+// Histogram counts observations in buckets
+type Histogram struct {
+	// Buckets are the upper bounds of the buckets, sorted
+	Buckets []float64 `json:"buckets"`
+	// Counts are the number of observations lower than or equal to each bucket upper bound
+	Counts []int   `json:"counts"`
+	Count  int     `json:"count"`
+	Sum    float64 `json:"sum"`
+}
+
+// NewMetrics returns empty Metrics, the simulation duration histogram using the given buckets
+// (DefaultDurationBuckets when nil)
+func NewMetrics(buckets []float64) *Metrics {
+	if buckets == nil {
+		buckets = DefaultDurationBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &Metrics{
+		stats: MissionStats{
+			RobotsLost: make(map[string]int),
+			Commands:   make(map[string]int),
+			Duration:   Histogram{Buckets: buckets, Counts: make([]int, len(buckets))},
+		},
+		now: time.Now,
+	}
+}
+
+// Explore explores a mission with send (ie: m.SendInstructions), recording its statistics from its events
+// statistics of a mission are only added once explored
+func (s *Metrics) Explore(m *MarsExplorer, send func()) {
+	o := &statsObserver{stats: MissionStats{MissionsRun: 1, RobotsLost: make(map[string]int), Commands: make(map[string]int)}}
+	for i := range m.Robots {
+		if m.Robots[i].Cursor == 0 && !m.Robots[i].Done() && !m.isRobotOffBound(m.Robots[i]) {
+			o.stats.RobotsDeployed++
+		}
+	}
+
+	n := len(m.observers)
+	m.Observe(o)
+	start := s.now()
+	send()
+	duration := s.now().Sub(start)
+	m.observers = append(m.observers[:n], m.observers[n+1:]...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stats.add(o.stats)
+	s.stats.Duration.observe(duration.Seconds())
+}
+
+// Stats returns a copy of the statistics collected so far
+func (s *Metrics) Stats() MissionStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := MissionStats{
+		RobotsLost: make(map[string]int, len(s.stats.RobotsLost)),
+		Commands:   make(map[string]int, len(s.stats.Commands)),
+		Duration:   s.stats.Duration,
+	}
+	stats.add(s.stats)
+	stats.Duration.Buckets = append([]float64(nil), s.stats.Duration.Buckets...)
+	stats.Duration.Counts = append([]int(nil), s.stats.Duration.Counts...)
+
+	return stats
+}
+
+// WritePrometheus writes the statistics in the Prometheus text exposition format
+func (s *Metrics) WritePrometheus(w io.Writer) error {
+	stats := s.Stats()
+	ew := &errWriter{w: w}
+
+	counter(ew, "mars_missions_total", "Missions explored.")
+	ew.printf("mars_missions_total %d\n", stats.MissionsRun)
+	counter(ew, "mars_robots_deployed_total", "Robots starting their instructions.")
+	ew.printf("mars_robots_deployed_total %d\n", stats.RobotsDeployed)
+	counter(ew, "mars_robots_lost_total", "Robots lost by cause, depleted robots included.")
+	for _, cause := range sortedKeys(stats.RobotsLost) {
+		ew.printf("mars_robots_lost_total{cause=%q} %d\n", cause, stats.RobotsLost[cause])
+	}
+	counter(ew, "mars_scent_saves_total", "Forward moves ignored because of a scent.")
+	ew.printf("mars_scent_saves_total %d\n", stats.ScentSaves)
+	counter(ew, "mars_commands_total", "Commands executed by command, ignored moves excluded.")
+	for _, c := range sortedKeys(stats.Commands) {
+		ew.printf("mars_commands_total{command=%q} %d\n", c, stats.Commands[c])
+	}
+
+	h := stats.Duration
+	ew.printf("# HELP mars_simulation_duration_seconds Time spent exploring a mission.\n")
+	ew.printf("# TYPE mars_simulation_duration_seconds histogram\n")
+	for i, b := range h.Buckets {
+		ew.printf("mars_simulation_duration_seconds_bucket{le=%q} %d\n", strconv.FormatFloat(b, 'g', -1, 64), h.Counts[i])
+	}
+	ew.printf("mars_simulation_duration_seconds_bucket{le=\"+Inf\"} %d\n", h.Count)
+	ew.printf("mars_simulation_duration_seconds_sum %s\n", strconv.FormatFloat(h.Sum, 'g', -1, 64))
+	ew.printf("mars_simulation_duration_seconds_count %d\n", h.Count)
+
+	return ew.err
+}
+
+// add adds the counters of other statistics, the histogram excluded
+func (s *MissionStats) add(o MissionStats) {
+	s.MissionsRun += o.MissionsRun
+	s.RobotsDeployed += o.RobotsDeployed
+	s.ScentSaves += o.ScentSaves
+	for cause, n := range o.RobotsLost {
+		s.RobotsLost[cause] += n
+	}
+	for c, n := range o.Commands {
+		s.Commands[c] += n
+	}
+}
+
+// observe records an observation in the histogram
+func (h *Histogram) observe(v float64) {
+	for i, b := range h.Buckets {
+		if v <= b {
+			h.Counts[i]++
+		}
+	}
+	h.Count++
+	h.Sum += v
+}
+
+// statsObserver counts the events of a mission
+type statsObserver struct {
+	NopObserver
+	stats MissionStats
+}
+
+// Moved counts a command executed, a forward move of several grid points counting once
+func (o *statsObserver) Moved(e MovedEvent) {
+	if e.Step == 0 {
+		o.stats.Commands[e.Command]++
+	}
+}
+
+// Blocked counts a scent save
+func (o *statsObserver) Blocked(e BlockedEvent) {
+	if e.By == BlockedByScent {
+		o.stats.ScentSaves++
+	}
+}
+
+// Lost counts a robot lost
+func (o *statsObserver) Lost(e LostEvent) {
+	o.stats.RobotsLost[e.Loss.Cause]++
+}
+
+// errWriter writes formatted text until the first error
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err == nil {
+		_, ew.err = fmt.Fprintf(ew.w, format, args...)
+	}
+}
+
+// counter writes the HELP and TYPE lines of a counter
+func counter(ew *errWriter, name, help string) {
+	ew.printf("# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+}
+
+// sortedKeys returns the keys of a map, sorted
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"bytes"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tickingClock returns a clock moving forward by tick every time it is read
+func tickingClock(tick time.Duration) func() time.Time {
+	now := time.Unix(0, 0)
+	return func() time.Time {
+		now = now.Add(tick)
+		return now
+	}
+}
+
+func TestMetrics_Explore(t *testing.T) {
+	metrics := NewMetrics([]float64{1, 0.01})
+	metrics.now = tickingClock(5 * time.Millisecond)
+
+	m := sampleMission()
+	m.Robots = append(m.Robots, Robot{PosX: 9, PosY: 9, Direction: "N", Instructions: []string{"F"}})
+	m.Observe(NopObserver{})
+	metrics.Explore(m, m.SendInstructions)
+
+	depleted := &MarsExplorer{
+		Surface: &Surface{MaxX: 5, MaxY: 3},
+		Robots:  []Robot{{Direction: "N", Instructions: []string{"F", "F"}, Battery: &Battery{Level: 2, Costs: DefaultEnergyCosts}}},
+	}
+	metrics.Explore(depleted, func() { depleted.SendInstructionsParallel(2) })
+
+	want := MissionStats{
+		MissionsRun:    2,
+		RobotsDeployed: 4,
+		RobotsLost:     map[string]int{LossCauseEdge: 1, LossCauseEnergy: 1},
+		ScentSaves:     1,
+		Commands:       map[string]int{CommandForward: 13, CommandLeft: 7, CommandRight: 6},
+		Duration:       Histogram{Buckets: []float64{0.01, 1}, Counts: []int{2, 2}, Count: 2, Sum: 0.01},
+	}
+	if got := metrics.Stats(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Stats() got %+v, want %+v", got, want)
+	}
+
+	if len(m.observers) != 1 {
+		t.Errorf("Explore() left %d observers, want 1", len(m.observers))
+	}
+}
+
+func TestMetrics_WritePrometheus(t *testing.T) {
+	metrics := NewMetrics([]float64{0.001, 0.1})
+	metrics.now = tickingClock(10 * time.Millisecond)
+	m := sampleMission()
+	metrics.Explore(m, m.SendInstructions)
+
+	var out bytes.Buffer
+	if err := metrics.WritePrometheus(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# HELP mars_missions_total Missions explored.
+# TYPE mars_missions_total counter
+mars_missions_total 1
+# HELP mars_robots_deployed_total Robots starting their instructions.
+# TYPE mars_robots_deployed_total counter
+mars_robots_deployed_total 3
+# HELP mars_robots_lost_total Robots lost by cause, depleted robots included.
+# TYPE mars_robots_lost_total counter
+mars_robots_lost_total{cause="edge"} 1
+# HELP mars_scent_saves_total Forward moves ignored because of a scent.
+# TYPE mars_scent_saves_total counter
+mars_scent_saves_total 1
+# HELP mars_commands_total Commands executed by command, ignored moves excluded.
+# TYPE mars_commands_total counter
+mars_commands_total{command="F"} 12
+mars_commands_total{command="L"} 7
+mars_commands_total{command="R"} 6
+# HELP mars_simulation_duration_seconds Time spent exploring a mission.
+# TYPE mars_simulation_duration_seconds histogram
+mars_simulation_duration_seconds_bucket{le="0.001"} 0
+mars_simulation_duration_seconds_bucket{le="0.1"} 1
+mars_simulation_duration_seconds_bucket{le="+Inf"} 1
+mars_simulation_duration_seconds_sum 0.01
+mars_simulation_duration_seconds_count 1
+`
+	if out.String() != want {
+		t.Errorf("WritePrometheus() got\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestMetrics_concurrent(t *testing.T) {
+	metrics := NewMetrics(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m := sampleMission()
+			metrics.Explore(m, m.SendInstructions)
+			metrics.Stats()
+		}()
+	}
+	wg.Wait()
+
+	if got := metrics.Stats(); got.MissionsRun != 20 || got.RobotsDeployed != 60 || got.Duration.Count != 20 {
+		t.Errorf("Stats() got %+v", got)
+	}
+}
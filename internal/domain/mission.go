@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Mission returns the MarsExplorer as the lines of a mission file which can be read back by MarsBuilder.Build
+// robots are written with their current pose, scents are not part of a mission
+func (m *MarsExplorer) Mission() []string {
+	lines := []string{fmt.Sprintf("%d %d", m.Surface.MaxX, m.Surface.MaxY)}
+
+	terrain := make([]Point, 0, len(m.Surface.Terrain))
+	for p := range m.Surface.Terrain {
+		terrain = append(terrain, p)
+	}
+	sort.Slice(terrain, func(i, j int) bool {
+		if terrain[i].Y != terrain[j].Y {
+			return terrain[i].Y < terrain[j].Y
+		}
+		return terrain[i].X < terrain[j].X
+	})
+	for _, p := range terrain {
+		lines = append(lines, fmt.Sprintf("%s %d %d", m.Surface.Terrain[p], p.X, p.Y))
+	}
+
+	for i := range m.Robots {
+		lines = append(lines, m.Robots[i].PositionLine())
+		// a robot without instructions has no instruction line, an empty one being skipped when read back
+		if len(m.Robots[i].Instructions) > 0 {
+			lines = append(lines, strings.Join(m.Robots[i].Instructions, ""))
+		}
+		lines = append(lines, "")
+	}
+
+	return lines
+}
+
+// PositionLine returns the robot position line as found in a mission file, including its options
+// ie: "1 1 E id=scout-1 type=hover energy=40"
+func (r *Robot) PositionLine() string {
+	s := fmt.Sprintf("%d %d %s", r.PosX, r.PosY, r.Direction)
+
+	if r.ID != "" {
+		s = fmt.Sprintf("%s id=%s", s, r.ID)
+	}
+
+	if r.Type != "" {
+		s = fmt.Sprintf("%s type=%s", s, r.Type)
+	}
+
+	if r.Battery != nil {
+		s = fmt.Sprintf("%s energy=%d", s, r.Battery.Level)
+	}
+
+	return s
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestMarsExplorer_Mission(t *testing.T) {
+	m := &MarsExplorer{
+		Surface: &Surface{MaxX: 5, MaxY: 3, Terrain: map[Point]string{{X: 2, Y: 2}: TerrainRock, {X: 4, Y: 1}: TerrainCrater}},
+		Robots: []Robot{
+			{PosX: 1, PosY: 1, Direction: "E", Instructions: []string{"R", "F", "[O?L:F]"}},
+			{PosX: 2, PosY: 1, Direction: "W"},
+			{ID: "scout-1", Type: RobotTypeHover, PosX: 3, PosY: 2, Direction: "N", Battery: &Battery{Level: 40}, Instructions: []string{"F"}},
+		},
+	}
+	want := []string{
+		"5 3",
+		"crater 4 1",
+		"rock 2 2",
+		"1 1 E",
+		"RF[O?L:F]",
+		"",
+		"2 1 W",
+		"",
+		"3 2 N id=scout-1 type=hover energy=40",
+		"F",
+		"",
+	}
+
+	got := m.Mission()
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Mission() got %v, want %v", got, want)
+	}
+
+	l := logrus.New()
+	l.SetOutput(ioutil.Discard)
+	mb := NewMarsBuilder(NewLogrusLogger(l))
+	built, err := mb.Build(got)
+	if err != nil {
+		t.Fatalf("Mission() can't be built back, got %v", err)
+	}
+	built.Robots[2].Battery.Costs = nil
+	built.SetLogger(nil)
+	if !reflect.DeepEqual(built, m) {
+		t.Errorf("Mission() built back %v, want %v", built, m)
+	}
+}
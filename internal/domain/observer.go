@@ -0,0 +1,153 @@
+package domain
+
+const (
+	EventMoved     = "moved"
+	EventBlocked   = "blocked"
+	EventLost      = "lost"
+	EventScentLeft = "scent-left"
+)
+
+// Event is implemented by every event of a simulation, Name being one of the Event* constants
+type Event interface {
+	Name() string
+}
+
+// MovedEvent is sent every time a robot executes a command
+// a forward move of a robot moving several grid points at once (see Capabilities.Speed) sends one event per grid point
+// the move making a robot lost is sent as well, To being then off the grid or in a crater
+type MovedEvent struct {
+	// Robot is the robot index within MarsExplorer.Robots
+	Robot int
+	// ID is the robot ID, if any
+	ID string
+	// Instruction is the index within the robot instructions, a conditional counting as a single instruction
+	Instruction int
+	Command     string
+	// Step is the grid point index within a forward move, always 0 for a turn
+	Step int
+	From Pose
+	To   Pose
+}
+
+// BlockedEvent is sent every time a forward move gets ignored
+type BlockedEvent struct {
+	Robot       int
+	ID          string
+	Instruction int
+	Command     string
+	Pose        Pose
+	// By is what made the move ignored, BlockedByScent, BlockedByTerrain or BlockedByEdge (see EdgeStop)
+	By string
+}
+
+// LostEvent is sent when a robot stops operating, depleted robots included (Loss.Cause being LossCauseEnergy)
+type LostEvent struct {
+	Robot int
+	ID    string
+	// Pose is where the robot is reported, the last grid point it occupied
+	Pose Pose
+	Loss Loss
+}
+
+// ScentLeftEvent is sent when a lost robot leaves a scent behind
+type ScentLeftEvent struct {
+	Robot int
+	ID    string
+	// Pose is the scent grid point and the direction of the fatal move
+	Pose Pose
+}
+
+// Name returns EventMoved
+func (MovedEvent) Name() string { return EventMoved }
+
+// Name returns EventBlocked
+func (BlockedEvent) Name() string { return EventBlocked }
+
+// Name returns EventLost
+func (LostEvent) Name() string { return EventLost }
+
+// Name returns EventScentLeft
+func (ScentLeftEvent) Name() string { return EventScentLeft }
+
+// Observer receives the events of a simulation as they happen, see MarsExplorer.Observe
+// callbacks are called synchronously from the simulation loop
+type Observer interface {
+	Moved(e MovedEvent)
+	Blocked(e BlockedEvent)
+	Lost(e LostEvent)
+	ScentLeft(e ScentLeftEvent)
+}
+
+// NopObserver ignores every event, it is meant to be embedded by observers interested in a few events only
+type NopObserver struct{}
+
+// Moved does nothing
+func (NopObserver) Moved(MovedEvent) {}
+
+// Blocked does nothing
+func (NopObserver) Blocked(BlockedEvent) {}
+
+// Lost does nothing
+func (NopObserver) Lost(LostEvent) {}
+
+// ScentLeft does nothing
+func (NopObserver) ScentLeft(ScentLeftEvent) {}
+
+// ChannelObserver sends every event to its channel, blocking the simulation while the channel is full
+type ChannelObserver chan<- Event
+
+// Moved sends the event to the channel
+func (c ChannelObserver) Moved(e MovedEvent) { c <- e }
+
+// Blocked sends the event to the channel
+func (c ChannelObserver) Blocked(e BlockedEvent) { c <- e }
+
+// Lost sends the event to the channel
+func (c ChannelObserver) Lost(e LostEvent) { c <- e }
+
+// ScentLeft sends the event to the channel
+func (c ChannelObserver) ScentLeft(e ScentLeftEvent) { c <- e }
+
+// EventRecorder records every event in the order they are sent
+type EventRecorder struct {
+	Events []Event
+}
+
+// Moved records the event
+func (r *EventRecorder) Moved(e MovedEvent) { r.Events = append(r.Events, e) }
+
+// Blocked records the event
+func (r *EventRecorder) Blocked(e BlockedEvent) { r.Events = append(r.Events, e) }
+
+// Lost records the event
+func (r *EventRecorder) Lost(e LostEvent) { r.Events = append(r.Events, e) }
+
+// ScentLeft records the event
+func (r *EventRecorder) ScentLeft(e ScentLeftEvent) { r.Events = append(r.Events, e) }
+
+// Observe registers an observer which will be notified of the events of the simulation
+func (m *MarsExplorer) Observe(o Observer) {
+	m.observers = append(m.observers, o)
+}
+
+// notify sends an event to every registered observer, logging it as well (see SetLogger)
+func (m *MarsExplorer) notify(e Event) {
+	m.logEvent(e)
+	for _, o := range m.observers {
+		switch e := e.(type) {
+		case MovedEvent:
+			o.Moved(e)
+		case BlockedEvent:
+			o.Blocked(e)
+		case LostEvent:
+			o.Lost(e)
+		case ScentLeftEvent:
+			o.ScentLeft(e)
+		}
+	}
+}
+
+// Pose returns the robot pose
+func (r *Robot) Pose() Pose {
+	return Pose{X: r.PosX, Y: r.PosY, Direction: r.Direction}
+}
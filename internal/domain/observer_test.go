@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarsExplorer_Observe(t *testing.T) {
+	m := &MarsExplorer{
+		Surface: &Surface{MaxX: 1, MaxY: 1},
+		Robots: []Robot{
+			{ID: "scout-1", PosX: 1, PosY: 0, Direction: "N", Instructions: []string{"L", "F", "F"}},
+			{PosX: 0, PosY: 0, Direction: "N", Instructions: []string{"[S?L]", "F", "F"}, Type: RobotTypeHover},
+		},
+	}
+	want := []Event{
+		MovedEvent{Robot: 0, ID: "scout-1", Instruction: 0, Command: "L", From: Pose{1, 0, "N"}, To: Pose{1, 0, "W"}},
+		MovedEvent{Robot: 0, ID: "scout-1", Instruction: 1, Command: "F", From: Pose{1, 0, "W"}, To: Pose{0, 0, "W"}},
+		MovedEvent{Robot: 0, ID: "scout-1", Instruction: 2, Command: "F", From: Pose{0, 0, "W"}, To: Pose{-1, 0, "W"}},
+		LostEvent{Robot: 0, ID: "scout-1", Pose: Pose{0, 0, "W"}, Loss: Loss{Cause: LossCauseEdge, Instruction: 2, Target: Point{X: -1, Y: 0}}},
+		ScentLeftEvent{Robot: 0, ID: "scout-1", Pose: Pose{0, 0, "W"}},
+		MovedEvent{Robot: 1, Instruction: 1, Command: "F", Step: 0, From: Pose{0, 0, "N"}, To: Pose{0, 1, "N"}},
+		MovedEvent{Robot: 1, Instruction: 1, Command: "F", Step: 1, From: Pose{0, 1, "N"}, To: Pose{0, 2, "N"}},
+		LostEvent{Robot: 1, Pose: Pose{0, 1, "N"}, Loss: Loss{Cause: LossCauseEdge, Instruction: 1, Target: Point{X: 0, Y: 2}}},
+		ScentLeftEvent{Robot: 1, Pose: Pose{0, 1, "N"}},
+	}
+
+	recorder := &EventRecorder{}
+	m.Observe(recorder)
+	m.SendInstructions()
+
+	if !reflect.DeepEqual(recorder.Events, want) {
+		t.Errorf("Observe() got events %v, want %v", recorder.Events, want)
+	}
+}
+
+func TestMarsExplorer_Observe_Blocked(t *testing.T) {
+	m := &MarsExplorer{
+		Surface: &Surface{MaxX: 1, MaxY: 1, Terrain: map[Point]string{{X: 1, Y: 1}: TerrainRock}},
+		Robots: []Robot{
+			{PosX: 0, PosY: 1, Direction: "E", Instructions: []string{"F"}},
+		},
+	}
+	want := []Event{
+		BlockedEvent{Robot: 0, Instruction: 0, Command: "F", Pose: Pose{0, 1, "E"}, By: BlockedByTerrain},
+	}
+
+	events := make(chan Event, 10)
+	m.Observe(ChannelObserver(events))
+	m.SendInstructions()
+	close(events)
+
+	got := make([]Event, 0)
+	for e := range events {
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Observe() got events %v, want %v", got, want)
+	}
+	if got[0].Name() != EventBlocked {
+		t.Errorf("Event.Name() got %s, want %s", got[0].Name(), EventBlocked)
+	}
+}
@@ -0,0 +1,138 @@
+package domain
+
+import "strings"
+
+// quarterTurns is the number of clockwise quarter turns made by each turning command
+var quarterTurns = map[string]int{
+	CommandRight: 1,
+	CommandUTurn: 2,
+	CommandLeft:  3,
+}
+
+// OptimizeInstructions returns the shortest instructions with the same trajectory regardless of the surface
+// every run of turns is replaced by its net rotation (ie: "LR" is dropped, "RRR" becomes "L")
+// and conditional branches are optimized on their own
+// less turns means less energy drained for a robot carrying a battery
+func OptimizeInstructions(instructions []string, robotType string) []string {
+	r := &Robot{Type: robotType}
+	optimized := make([]string, 0, len(instructions))
+	turns := 0
+	for _, c := range instructions {
+		if q, ok := quarterTurns[c]; ok {
+			turns += q
+			continue
+		}
+
+		optimized = append(optimized, r.rotation(turns)...)
+		turns = 0
+
+		if isConditional(c) {
+			cond, err := ParseConditional(c)
+			if err == nil {
+				c = conditionalOpen + cond.Sensor + conditionalThen + strings.Join(OptimizeInstructions(cond.Then, robotType), "")
+				if otherwise := OptimizeInstructions(cond.Else, robotType); len(otherwise) > 0 {
+					c += conditionalElse + strings.Join(otherwise, "")
+				}
+				c += conditionalClose
+			}
+		}
+		optimized = append(optimized, c)
+	}
+
+	return append(optimized, r.rotation(turns)...)
+}
+
+// Optimize returns a copy of the mission where the instructions of every robot are the shortest ones
+// with the same trajectory on this surface
+// the mission is explored on a copy first so only the commands which actually moved or turned a robot are kept:
+// moves ignored because of a scent or a terrain are dropped, conditionals are replaced by the branch taken
+// and anything after a robot got lost or depleted is dropped, turns being then optimized as in OptimizeInstructions
+// a robot carrying a battery keeps its instructions up to the one which stopped it, since every dropped
+// or collapsed command would drain a different amount of energy and change where it gets depleted
+func (m *MarsExplorer) Optimize() *MarsExplorer {
+	optimized := m.Copy()
+
+	explored := m.Copy()
+	recorder := &commandRecorder{executed: make([][]string, len(explored.Robots))}
+	explored.Observe(recorder)
+	explored.SendInstructions()
+
+	for i := range optimized.Robots {
+		if optimized.Robots[i].Battery != nil {
+			if loss := explored.Robots[i].Loss; loss != nil {
+				optimized.Robots[i].Instructions = optimized.Robots[i].Instructions[:loss.Instruction+1]
+			}
+			continue
+		}
+		optimized.Robots[i].Instructions = OptimizeInstructions(recorder.executed[i], optimized.Robots[i].Type)
+	}
+
+	return optimized
+}
+
+// commandRecorder records the commands executed by each robot
+type commandRecorder struct {
+	NopObserver
+	executed [][]string
+}
+
+// Moved records the command once, even if it moved the robot several grid points
+func (c *commandRecorder) Moved(e MovedEvent) {
+	if e.Step == 0 {
+		c.executed[e.Robot] = append(c.executed[e.Robot], e.Command)
+	}
+}
+
+// Copy returns a deep copy of the MarsExplorer, the surface being shared
+func (m *MarsExplorer) Copy() *MarsExplorer {
+	c := &MarsExplorer{
+		Surface:         m.Surface,
+		Robots:          make([]Robot, len(m.Robots)),
+		Scents:          append([]Scent(nil), m.Scents...),
+		Rules:           m.Rules,
+		MaxInstructions: m.MaxInstructions,
+	}
+
+	for i := range m.Robots {
+		c.Robots[i] = m.Robots[i].Copy()
+	}
+
+	return c
+}
+
+// Copy returns a deep copy of the robot
+func (r *Robot) Copy() Robot {
+	c := *r
+	if r.Instructions != nil {
+		c.Instructions = append(make([]string, 0, len(r.Instructions)), r.Instructions...)
+	}
+
+	if r.Battery != nil {
+		battery := *r.Battery
+		c.Battery = &battery
+	}
+
+	if r.Loss != nil {
+		loss := *r.Loss
+		c.Loss = &loss
+	}
+
+	return c
+}
+
+// rotation returns the shortest commands making the given number of clockwise quarter turns
+func (r *Robot) rotation(turns int) []string {
+	switch turns % 4 {
+	case 1:
+		return []string{CommandRight}
+	case 2:
+		if r.Supports(CommandUTurn) {
+			return []string{CommandUTurn}
+		}
+		return []string{CommandRight, CommandRight}
+	case 3:
+		return []string{CommandLeft}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOptimizeInstructions(t *testing.T) {
+	tests := []struct {
+		name         string
+		instructions string
+		robotType    string
+		want         string
+	}{
+		{
+			name:         "turns cancelling each other are dropped",
+			instructions: "FLRFRLLLLF",
+			want:         "FFRF",
+		},
+		{
+			name:         "three turns are a single one",
+			instructions: "RRRFLLLF",
+			want:         "LFRF",
+		},
+		{
+			name:         "half turns",
+			instructions: "FLLFRRF",
+			want:         "FRRFRRF",
+		},
+		{
+			name:         "half turns as u-turn when supported",
+			instructions: "FLLFRRFU",
+			robotType:    RobotTypeTracked,
+			want:         "FUFUFU",
+		},
+		{
+			name:         "trailing turns are kept",
+			instructions: "FRRR",
+			want:         "FL",
+		},
+		{
+			name:         "conditionals are barriers and their branches get optimized",
+			instructions: "RR[O?LLL:RL]RRF",
+			want:         "RR[O?R]RRF",
+		},
+		{
+			name:         "nested conditionals",
+			instructions: "[O?[S?RRRR:LLL]:FRRR]",
+			want:         "[O?[S?:R]:FL]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instructions, err := TokenizeInstructions(tt.instructions)
+			if err != nil {
+				t.Fatalf("TokenizeInstructions() error = %v", err)
+			}
+			got := strings.Join(OptimizeInstructions(instructions, tt.robotType), "")
+			if got != tt.want {
+				t.Errorf("OptimizeInstructions() got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarsExplorer_Optimize(t *testing.T) {
+	m := &MarsExplorer{
+		Surface: &Surface{MaxX: 5, MaxY: 3, Terrain: map[Point]string{{X: 5, Y: 0}: TerrainRock}},
+		Robots: []Robot{
+			{PosX: 1, PosY: 1, Direction: "E", Instructions: []string{"R", "F", "R", "F", "R", "F", "R", "F"}},
+			{PosX: 3, PosY: 2, Direction: "N", Instructions: []string{"F", "R", "R", "F", "L", "L", "F", "F", "R", "R", "F", "L", "L"}},
+			{PosX: 0, PosY: 3, Direction: "W", Instructions: []string{"L", "L", "F", "F", "F", "L", "F", "L", "F", "L"}},
+			{PosX: 4, PosY: 0, Direction: "E", Instructions: []string{"F", "[O?L:R]", "F"}},
+		},
+	}
+	want := []string{
+		"RFRFRFRF",
+		"FRRFRRFF",
+		"RRFFFRRFL",
+		"RF",
+	}
+
+	optimized := m.Optimize()
+
+	for i, r := range optimized.Robots {
+		if got := strings.Join(r.Instructions, ""); got != want[i] {
+			t.Errorf("Optimize() robot %d got %s, want %s", i, got, want[i])
+		}
+	}
+
+	// the optimized mission ends up exactly like the original one
+	m.SendInstructions()
+	optimized.SendInstructions()
+	for i := range m.Robots {
+		if m.Robots[i].ToString() != optimized.Robots[i].ToString() {
+			t.Errorf("Optimize() robot %d got %s, want %s", i, optimized.Robots[i].ToString(), m.Robots[i].ToString())
+		}
+	}
+	if !reflect.DeepEqual(m.Scents, optimized.Scents) {
+		t.Errorf("Optimize() got scents %v, want %v", optimized.Scents, m.Scents)
+	}
+}
+
+func TestMarsExplorer_OptimizeBattery(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []string
+		want  []string
+	}{
+		{
+			name:  "depleted robots keep their commands up to the depleting one",
+			input: []string{"5 3", "1 1 E energy=5", "RRRRFRF", "0 0 N energy=3", "RRRRF"},
+			want:  []string{"RRRRF", "RRRR"},
+		},
+		{
+			name:  "robot lost with energy left",
+			input: []string{"5 3", "3 3 N energy=10", "RLFRF"},
+			want:  []string{"RLF"},
+		},
+		{
+			name:  "robot done with energy left",
+			input: []string{"5 3", "1 1 E energy=10", "RLFF", "1 1 E", "RLFF"},
+			want:  []string{"RLFF", "FF"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mb := NewMarsBuilder(nil)
+			m, err := mb.Build(tt.input)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+
+			optimized := m.Optimize()
+			for i, r := range optimized.Robots {
+				if got := strings.Join(r.Instructions, ""); got != tt.want[i] {
+					t.Errorf("Optimize() robot %d got %s, want %s", i, got, tt.want[i])
+				}
+			}
+
+			m.SendInstructions()
+			optimized.SendInstructions()
+			for i := range m.Robots {
+				if m.Robots[i].ToString() != optimized.Robots[i].ToString() {
+					t.Errorf("Optimize() robot %d got %s, want %s", i, optimized.Robots[i].ToString(), m.Robots[i].ToString())
+				}
+			}
+		})
+	}
+}
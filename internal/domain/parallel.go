@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"runtime"
+	"sync"
+)
+
+// speculation is the outcome of a robot explored on its own, against the scents known before the mission
+type speculation struct {
+	robot Robot
+	// poses are every pose the robot stood at, where it read scents
+	poses []Pose
+	// scent is the scent the robot left when lost, if any
+	scent *Scent
+	// events are the events sent while exploring, recorded only when the mission is observed
+	events []Event
+}
+
+// speculationRecorder records the poses of a robot explored on its own, along with its events when needed
+type speculationRecorder struct {
+	s      *speculation
+	events bool
+}
+
+func (r *speculationRecorder) Moved(e MovedEvent) {
+	r.s.poses = append(r.s.poses, e.To)
+	r.record(e)
+}
+
+func (r *speculationRecorder) Blocked(e BlockedEvent)     { r.record(e) }
+func (r *speculationRecorder) Lost(e LostEvent)           { r.record(e) }
+func (r *speculationRecorder) ScentLeft(e ScentLeftEvent) { r.record(e) }
+
+func (r *speculationRecorder) record(e Event) {
+	if r.events {
+		r.s.events = append(r.s.events, e)
+	}
+}
+
+// SendInstructionsParallel explores the surface like SendInstructions, giving the very same robots, scents and events,
+// robots being explored speculatively on a pool of workers (GOMAXPROCS when workers <= 0)
+//
+// robots depend on each other through scents only, unless reading the B sensor: every robot is first explored
+// on its own against the scents known before the mission, then results are committed in the robots order,
+// a robot which stood on a scent left by an earlier robot being explored again from scratch.
+// Robots reading the B sensor, depending on where the other robots are, are always explored when committed.
+// It returns the number of robots explored again.
+func (m *MarsExplorer) SendInstructionsParallel(workers int) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// full slice expression, appending to the scents while speculating must not share the backing array
+	known := m.Scents[:len(m.Scents):len(m.Scents)]
+	speculations := make([]*speculation, len(m.Robots))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ri := range indexes {
+				speculations[ri] = m.speculate(ri, known)
+			}
+		}()
+	}
+	for ri := range m.Robots {
+		if m.isRobotOffBound(m.Robots[ri]) || m.Robots[ri].Done() || readsSensor(m.Robots[ri].Instructions[m.Robots[ri].Cursor:], SensorBlocked) {
+			continue
+		}
+		indexes <- ri
+	}
+	close(indexes)
+	wg.Wait()
+
+	// scents left by the robots committed so far
+	left := make(map[Scent]struct{})
+	reexecuted := 0
+	for ri := range m.Robots {
+		if m.isRobotOffBound(m.Robots[ri]) {
+			continue
+		}
+
+		if !m.Robots[ri].Done() {
+			m.deployed(ri)
+		}
+		s := speculations[ri]
+		if s != nil && !s.conflicts(left, m.Rules) {
+			m.Robots[ri] = s.robot
+			for _, e := range s.events {
+				m.notify(reindex(e, ri))
+			}
+			if s.scent != nil {
+				m.Scents = append(m.Scents, *s.scent)
+				left[m.Rules.scentKey(s.scent.PosX, s.scent.PosY, s.scent.Direction)] = struct{}{}
+			}
+			continue
+		}
+
+		if s != nil {
+			reexecuted++
+		}
+		scents := len(m.Scents)
+		for !m.Robots[ri].Done() {
+			m.step(ri)
+		}
+		for _, sc := range m.Scents[scents:] {
+			left[m.Rules.scentKey(sc.PosX, sc.PosY, sc.Direction)] = struct{}{}
+		}
+	}
+
+	return reexecuted
+}
+
+// speculate explores a robot on its own against the given scents
+func (m *MarsExplorer) speculate(ri int, scents []Scent) *speculation {
+	s := &speculation{poses: []Pose{m.Robots[ri].Pose()}}
+	alone := &MarsExplorer{
+		Surface: m.Surface,
+		Robots:  []Robot{m.Robots[ri].Copy()},
+		Scents:  scents,
+		Rules:   m.Rules,
+	}
+	alone.Observe(&speculationRecorder{s: s, events: len(m.observers) > 0 || m.logging()})
+
+	for !alone.Robots[0].Done() {
+		alone.step(0)
+	}
+
+	s.robot = alone.Robots[0]
+	if len(alone.Scents) > len(scents) {
+		s.scent = &alone.Scents[len(scents)]
+	}
+
+	return s
+}
+
+// conflicts tells if the robot stood on a scent it didn't know about while speculating
+func (s *speculation) conflicts(left map[Scent]struct{}, rules Rules) bool {
+	if len(left) == 0 {
+		return false
+	}
+
+	for _, p := range s.poses {
+		if _, ok := left[rules.scentKey(p.X, p.Y, p.Direction)]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reindex returns an event of a robot explored on its own as an event of the robot ri of the mission
+func reindex(e Event, ri int) Event {
+	switch e := e.(type) {
+	case MovedEvent:
+		e.Robot = ri
+		return e
+	case BlockedEvent:
+		e.Robot = ri
+		return e
+	case LostEvent:
+		e.Robot = ri
+		return e
+	case ScentLeftEvent:
+		e.Robot = ri
+		return e
+	default:
+		return e
+	}
+}
+
+// readsSensor tells if any of the instructions, nested conditionals included, reads the given sensor
+func readsSensor(instructions []string, sensor string) bool {
+	for _, c := range instructions {
+		if !isConditional(c) {
+			continue
+		}
+		cond, err := ParseConditional(c)
+		if err != nil {
+			continue
+		}
+		if cond.Sensor == sensor || readsSensor(cond.Then, sensor) || readsSensor(cond.Else, sensor) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// randomMission returns a mission crowded enough for robots to keep getting lost at the same edges
+func randomMission(rnd *rand.Rand, robots int) *MarsExplorer {
+	m := &MarsExplorer{
+		Surface: &Surface{MaxX: rnd.Intn(6), MaxY: rnd.Intn(6), Terrain: map[Point]string{}},
+	}
+	for i := 0; i < 3; i++ {
+		kind := TerrainRock
+		if rnd.Intn(2) == 0 {
+			kind = TerrainCrater
+		}
+		m.Surface.Terrain[Point{X: rnd.Intn(m.Surface.MaxX + 1), Y: rnd.Intn(m.Surface.MaxY + 1)}] = kind
+	}
+
+	types := []string{"", RobotTypeWheeled, RobotTypeTracked, RobotTypeHover}
+	directions := []string{DirectionNorth, DirectionEast, DirectionSouth, DirectionWest}
+	commands := []string{"F", "F", "F", "L", "R", "[S?L:F]", "[O?R:F]", "[S?[O?L:F]:F]"}
+	for i := 0; i < robots; i++ {
+		r := Robot{
+			Type:      types[rnd.Intn(len(types))],
+			PosX:      rnd.Intn(m.Surface.MaxX + 1),
+			PosY:      rnd.Intn(m.Surface.MaxY + 1),
+			Direction: directions[rnd.Intn(len(directions))],
+		}
+		if rnd.Intn(5) == 0 {
+			r.Battery = &Battery{Level: rnd.Intn(20), Costs: DefaultEnergyCosts}
+		}
+		for n := rnd.Intn(15); n > 0; n-- {
+			r.Instructions = append(r.Instructions, commands[rnd.Intn(len(commands))])
+		}
+		if r.Type == RobotTypeTracked && rnd.Intn(2) == 0 {
+			r.Instructions = append(r.Instructions, CommandUTurn, CommandForward)
+		}
+		if rnd.Intn(10) == 0 {
+			r.Instructions = append(r.Instructions, "[B?R:F]")
+		}
+		m.Robots = append(m.Robots, r)
+	}
+
+	return m
+}
+
+func TestMarsExplorer_SendInstructionsParallel(t *testing.T) {
+	rnd := rand.New(rand.NewSource(44))
+	reexecuted := 0
+
+	for i := 0; i < 200; i++ {
+		t.Run(fmt.Sprintf("mission %d", i), func(t *testing.T) {
+			sequential := randomMission(rnd, 1+rnd.Intn(60))
+			sequential.Rules = Rules{
+				Edge:  []string{EdgeLost, EdgeLost, EdgeStop}[rnd.Intn(3)],
+				Scent: []string{ScentPose, ScentPose, ScentCell, ScentOff}[rnd.Intn(4)],
+			}
+			parallel := sequential.Copy()
+
+			var want, got EventRecorder
+			sequential.Observe(&want)
+			parallel.Observe(&got)
+
+			sequential.SendInstructions()
+			reexecuted += parallel.SendInstructionsParallel(4)
+
+			if !reflect.DeepEqual(parallel.Robots, sequential.Robots) {
+				t.Errorf("SendInstructionsParallel() got robots %v, want %v", parallel.Robots, sequential.Robots)
+			}
+			if !reflect.DeepEqual(parallel.Scents, sequential.Scents) {
+				t.Errorf("SendInstructionsParallel() got scents %v, want %v", parallel.Scents, sequential.Scents)
+			}
+			if !reflect.DeepEqual(got.Events, want.Events) {
+				t.Errorf("SendInstructionsParallel() got events %v, want %v", got.Events, want.Events)
+			}
+			if !reflect.DeepEqual(parallel.Mission(), sequential.Mission()) {
+				t.Errorf("SendInstructionsParallel() got mission %v, want %v", parallel.Mission(), sequential.Mission())
+			}
+		})
+	}
+
+	// the missions are crowded enough for speculation to fail every now and then
+	if reexecuted == 0 {
+		t.Errorf("SendInstructionsParallel() never explored a robot again")
+	}
+}
+
+func TestMarsExplorer_SendInstructionsParallel_sample(t *testing.T) {
+	m := sampleMission()
+	reexecuted := m.SendInstructionsParallel(0)
+
+	want := sampleMission()
+	want.SendInstructions()
+	if !reflect.DeepEqual(m.Robots, want.Robots) || !reflect.DeepEqual(m.Scents, want.Scents) {
+		t.Errorf("SendInstructionsParallel() got %v, want %v", m, want)
+	}
+
+	// the third robot stood where the second one got lost
+	if reexecuted != 1 {
+		t.Errorf("SendInstructionsParallel() explored %d robots again, want 1", reexecuted)
+	}
+}
+
+func BenchmarkMarsExplorer_SendInstructions(b *testing.B) {
+	mission := randomMission(rand.New(rand.NewSource(44)), 100000)
+	mission.Surface.MaxX, mission.Surface.MaxY = 50, 50
+	for i := range mission.Robots {
+		mission.Robots[i].PosX, mission.Robots[i].PosY = i%51, (i/51)%51
+	}
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			mission.Copy().SendInstructions()
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			mission.Copy().SendInstructionsParallel(0)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Deploy adds a robot to the mission and returns its index within Robots
+// the robot must start on the grid and its ID, if any, must be unique within the mission
+func (m *MarsExplorer) Deploy(r Robot) (int, error) {
+	if m.isRobotOffBound(r) {
+		return 0, fmt.Errorf("robot can't be deployed off the grid at %d %d", r.PosX, r.PosY)
+	}
+
+	if r.ID != "" {
+		for i := range m.Robots {
+			if m.Robots[i].ID == r.ID {
+				return 0, fmt.Errorf("duplicate robot id %s", r.ID)
+			}
+		}
+	}
+
+	if err := r.ValidateInstructions(); err != nil {
+		return 0, err
+	}
+
+	m.Robots = append(m.Robots, r)
+
+	return len(m.Robots) - 1, nil
+}
+
+// Pilot appends a line of instructions to the robot ri (its index within Robots) and executes them right away
+// the robot must still be operating and its whole instructions can't exceed the instruction limit of the mission,
+// keeping the mission writable as a mission file (see Mission)
+func (m *MarsExplorer) Pilot(ri int, line string) error {
+	if ri < 0 || ri >= len(m.Robots) {
+		return fmt.Errorf("unknown robot %d", ri)
+	}
+
+	r := &m.Robots[ri]
+	if r.Lost || r.Depleted {
+		return fmt.Errorf("robot %s is not operating anymore", r.Label(ri))
+	}
+
+	if limit := m.instructionsLimit(); len(strings.Join(r.Instructions, ""))+len(line) > limit {
+		return fmt.Errorf("instructions are limited to %d", limit)
+	}
+
+	instructions, err := TokenizeInstructions(line)
+	if err != nil {
+		return err
+	}
+	if err := r.validate(instructions); err != nil {
+		return err
+	}
+
+	r.Instructions = append(r.Instructions, instructions...)
+	for !r.Done() {
+		m.step(ri)
+	}
+
+	return nil
+}
+
+// instructionsLimit returns the maximum length of the instructions piloted to a robot, MaxInstructions unless set
+func (m *MarsExplorer) instructionsLimit() int {
+	if m.MaxInstructions <= 0 {
+		return MaxInstructions
+	}
+
+	return m.MaxInstructions
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarsExplorer_Deploy(t *testing.T) {
+	tests := []struct {
+		name    string
+		robot   Robot
+		want    int
+		wantErr bool
+	}{
+		{
+			name:  "robot on the grid",
+			robot: Robot{PosX: 2, PosY: 2, Direction: "N"},
+			want:  1,
+		},
+		{
+			name:    "robot off the grid",
+			robot:   Robot{PosX: 6, PosY: 2, Direction: "N"},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate id",
+			robot:   Robot{ID: "scout", PosX: 2, PosY: 2, Direction: "N"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported instructions",
+			robot:   Robot{PosX: 2, PosY: 2, Direction: "N", Instructions: []string{"U"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MarsExplorer{
+				Surface: &Surface{MaxX: 5, MaxY: 3},
+				Robots:  []Robot{{ID: "scout", PosX: 0, PosY: 0, Direction: "N"}},
+			}
+
+			got, err := m.Deploy(tt.robot)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Deploy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Deploy() got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarsExplorer_Pilot(t *testing.T) {
+	// piloting the sample robots one line at a time gives the same outcome as sending the whole mission
+	m := sampleMission()
+	lines := [][]string{{"RFRF", "RFRF"}, {"FRRF", "LLFF", "RRFLL"}, {"LLFFFLF", "LFL"}}
+	for i := range m.Robots {
+		m.Robots[i].Instructions = nil
+		for _, l := range lines[i] {
+			err := m.Pilot(i, l)
+			if err != nil && !m.Robots[i].Lost {
+				t.Fatalf("Pilot() error = %v", err)
+			}
+		}
+	}
+
+	want := sampleMission()
+	want.SendInstructions()
+	want.Robots[1].Instructions = want.Robots[1].Instructions[:8]
+	want.Robots[1].Cursor = 8
+	if !reflect.DeepEqual(m.Robots, want.Robots) || !reflect.DeepEqual(m.Scents, want.Scents) {
+		t.Errorf("Pilot() got %v, want %v", m, want)
+	}
+
+	tests := []struct {
+		name string
+		ri   int
+		line string
+	}{
+		{name: "unknown robot", ri: 3, line: "F"},
+		{name: "lost robot", ri: 1, line: "F"},
+		{name: "unsupported command", ri: 0, line: "U"},
+		{name: "invalid conditional", ri: 0, line: "[X?F]"},
+		{name: "too many instructions", ri: 0, line: strings.Repeat("L", MaxInstructions)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := m.Pilot(tt.ri, tt.line); err == nil {
+				t.Errorf("Pilot() expected an error")
+			}
+		})
+	}
+}
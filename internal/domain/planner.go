@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Pose is a grid coordinate with an orientation
+type Pose struct {
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	Direction string `json:"direction"`
+}
+
+// Planner finds the shortest instructions to drive a robot from a pose to another
+// without ever risking an off-grid step, falling into a crater or bumping into an obstacle
+type Planner struct {
+	Surface *Surface
+	Scents  []Scent
+	// Obstacles are grid points to avoid on top of the surface terrain, ie: other robots
+	Obstacles map[Point]bool
+	// Type is the robot type the instructions are planned for, wheeled when empty
+	Type string
+}
+
+// ParsePose takes a position line, ie: "1 1 E", and returns its Pose or an error
+func ParsePose(line string) (Pose, error) {
+	l := strings.Split(line, " ")
+	if len(l) != 3 {
+		return Pose{}, fmt.Errorf("expected pose as <x> <y> <direction>, got %s", line)
+	}
+
+	x, err := strconv.Atoi(l[0])
+	if err != nil {
+		return Pose{}, fmt.Errorf("failed to convert pos X %s into integer, got %q", l[0], err)
+	}
+	y, err := strconv.Atoi(l[1])
+	if err != nil {
+		return Pose{}, fmt.Errorf("failed to convert pos Y %s into integer, got %q", l[1], err)
+	}
+
+	switch l[2] {
+	case DirectionNorth, DirectionEast, DirectionSouth, DirectionWest:
+	default:
+		return Pose{}, fmt.Errorf("unsupported direction %s", l[2])
+	}
+
+	return Pose{X: x, Y: y, Direction: l[2]}, nil
+}
+
+// Plan runs a breadth first search over the robot poses and returns the shortest instructions from start to goal
+func (p *Planner) Plan(start, goal Pose) ([]string, error) {
+	for _, pose := range []Pose{start, goal} {
+		if !p.Surface.Contains(pose.X, pose.Y) {
+			return nil, fmt.Errorf("pose %d %d %s is outside of the surface", pose.X, pose.Y, pose.Direction)
+		}
+		if _, _, ok := (&Robot{Direction: pose.Direction}).ahead(); !ok {
+			return nil, fmt.Errorf("unsupported direction %s", pose.Direction)
+		}
+	}
+
+	instructions, _, ok := p.search(start, func(pose Pose) bool {
+		return pose == goal
+	})
+	if !ok {
+		return nil, fmt.Errorf("no safe path from %d %d %s to %d %d %s",
+			start.X, start.Y, start.Direction, goal.X, goal.Y, goal.Direction)
+	}
+
+	return instructions, nil
+}
+
+// search runs a breadth first search over the robot poses from start
+// and returns the shortest instructions leading to the first pose matching found
+func (p *Planner) search(start Pose, found func(Pose) bool) ([]string, Pose, bool) {
+	type node struct {
+		parent  Pose
+		command string
+	}
+	visited := map[Pose]node{start: {}}
+	queue := []Pose{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if found(current) {
+			end := current
+			instructions := make([]string, 0)
+			for current != start {
+				n := visited[current]
+				instructions = append([]string{n.command}, instructions...)
+				current = n.parent
+			}
+			return instructions, end, true
+		}
+
+		for _, c := range []string{CommandForward, CommandLeft, CommandRight, CommandUTurn} {
+			next, ok := p.next(current, c)
+			if !ok {
+				continue
+			}
+			if _, seen := visited[next]; seen {
+				continue
+			}
+			visited[next] = node{parent: current, command: c}
+			queue = append(queue, next)
+		}
+	}
+
+	return nil, start, false
+}
+
+// next returns the pose reached by executing a command, false when the command is unsupported or unsafe
+func (p *Planner) next(pose Pose, c string) (Pose, bool) {
+	r := &Robot{Type: p.Type, PosX: pose.X, PosY: pose.Y, Direction: pose.Direction}
+	if !r.Supports(c) {
+		return pose, false
+	}
+
+	steps := 1
+	if c == CommandForward {
+		steps = r.Capabilities().Speed
+	}
+
+	m := &MarsExplorer{Surface: p.Surface, Scents: p.Scents}
+	for s := 0; s < steps; s++ {
+		if c == CommandForward {
+			if m.isThereARobotScent(*r, c) {
+				return pose, false
+			}
+			x, y, _ := r.ahead()
+			if !p.Surface.Contains(x, y) || p.Obstacles[Point{X: x, Y: y}] || m.terrainEffect(r, x, y) != TerrainPass {
+				return pose, false
+			}
+		}
+
+		if err := r.Execute(c); err != nil {
+			return pose, false
+		}
+	}
+
+	return Pose{X: r.PosX, Y: r.PosY, Direction: r.Direction}, true
+}
@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePose(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    Pose
+		wantErr bool
+	}{
+		{
+			name: "valid pose",
+			line: "1 2 E",
+			want: Pose{X: 1, Y: 2, Direction: "E"},
+		},
+		{
+			name:    "unsupported direction",
+			line:    "1 2 Q",
+			wantErr: true,
+		},
+		{
+			name:    "type error for Y",
+			line:    "1 B E",
+			wantErr: true,
+		},
+		{
+			name:    "missing direction",
+			line:    "1 2",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePose(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePose() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParsePose() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanner_Plan(t *testing.T) {
+	tests := []struct {
+		name    string
+		planner Planner
+		start   Pose
+		goal    Pose
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "already there",
+			planner: Planner{Surface: &Surface{MaxX: 3, MaxY: 3}},
+			start:   Pose{X: 1, Y: 1, Direction: "N"},
+			goal:    Pose{X: 1, Y: 1, Direction: "N"},
+			want:    "",
+		},
+		{
+			name:    "shortest path on a flat surface",
+			planner: Planner{Surface: &Surface{MaxX: 5, MaxY: 3}},
+			start:   Pose{X: 1, Y: 1, Direction: "E"},
+			goal:    Pose{X: 3, Y: 3, Direction: "N"},
+			want:    "FFLFF",
+		},
+		{
+			name: "rocks and obstacles are avoided",
+			planner: Planner{
+				Surface:   &Surface{MaxX: 2, MaxY: 1, Terrain: map[Point]string{{X: 1, Y: 0}: TerrainRock}},
+				Obstacles: map[Point]bool{{X: 1, Y: 1}: true},
+			},
+			start:   Pose{X: 0, Y: 0, Direction: "E"},
+			goal:    Pose{X: 2, Y: 0, Direction: "E"},
+			wantErr: true,
+		},
+		{
+			name: "tracked robots crawl over rocks and u-turn",
+			planner: Planner{
+				Surface: &Surface{MaxX: 2, MaxY: 1, Terrain: map[Point]string{{X: 1, Y: 0}: TerrainRock}},
+				Type:    RobotTypeTracked,
+			},
+			start: Pose{X: 0, Y: 0, Direction: "W"},
+			goal:  Pose{X: 2, Y: 0, Direction: "E"},
+			want:  "UFF",
+		},
+		{
+			name:    "hover robots move two grid points at a time",
+			planner: Planner{Surface: &Surface{MaxX: 4, MaxY: 4}, Type: RobotTypeHover},
+			start:   Pose{X: 0, Y: 0, Direction: "N"},
+			goal:    Pose{X: 0, Y: 4, Direction: "N"},
+			want:    "FF",
+		},
+		{
+			name:    "goal outside of the surface",
+			planner: Planner{Surface: &Surface{MaxX: 2, MaxY: 2}},
+			start:   Pose{X: 0, Y: 0, Direction: "N"},
+			goal:    Pose{X: 0, Y: 3, Direction: "N"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.planner.Plan(tt.start, tt.goal)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Plan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if strings.Join(got, "") != tt.want {
+				t.Errorf("Plan() got = %v, want %v", got, tt.want)
+			}
+
+			// the planned instructions must lead to the goal without getting lost
+			m := &MarsExplorer{
+				Surface: tt.planner.Surface,
+				Robots:  []Robot{{Type: tt.planner.Type, PosX: tt.start.X, PosY: tt.start.Y, Direction: tt.start.Direction, Instructions: got}},
+			}
+			m.SendInstructions()
+			r := m.Robots[0]
+			if !reflect.DeepEqual(Pose{X: r.PosX, Y: r.PosY, Direction: r.Direction}, tt.goal) || r.Lost {
+				t.Errorf("Plan() instructions lead to %s, want %v", r.ToString(), tt.goal)
+			}
+		})
+	}
+}
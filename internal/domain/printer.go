@@ -1,18 +1,115 @@
 package domain
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+
+	StatusOperating = "operating"
+	StatusLost      = "lost"
+	StatusDepleted  = "depleted"
+)
 
 type MarsReport interface {
 	Print()
+	Fprint(w io.Writer)
 }
 
 type Reporter struct {
 	Explorer *MarsExplorer
 }
 
+// JSONReporter prints a structured report of the robots
+type JSONReporter struct {
+	Explorer *MarsExplorer
+}
+
+// RobotReport is the structured report of a robot status
+type RobotReport struct {
+	ID string `json:"id,omitempty"`
+	// Type is the effective robot type, RobotTypeWheeled when not declared
+	Type      string `json:"type"`
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	Direction string `json:"direction"`
+	Status    string `json:"status"`
+	Energy    *int   `json:"energy,omitempty"`
+	Loss      *Loss  `json:"loss,omitempty"`
+}
+
+// NewReporter returns the MarsReport matching an output format
+func NewReporter(format string, explorer *MarsExplorer) (MarsReport, error) {
+	switch format {
+	case "", FormatText:
+		return Reporter{Explorer: explorer}, nil
+	case FormatJSON:
+		return JSONReporter{Explorer: explorer}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %s", format)
+	}
+}
+
 // Print range over the explorer robots and print their status over the standard output
 func (r Reporter) Print() {
+	r.Fprint(os.Stdout)
+}
+
+// Fprint range over the explorer robots and writes their status to w
+func (r Reporter) Fprint(w io.Writer) {
 	for _, r := range r.Explorer.Robots {
-		fmt.Println(r.ToString())
+		fmt.Fprintln(w, r.ToString())
 	}
 }
+
+// Print range over the explorer robots and print their status as a JSON document over the standard output
+func (r JSONReporter) Print() {
+	r.Fprint(os.Stdout)
+}
+
+// Fprint range over the explorer robots and writes their status as a JSON document to w
+func (r JSONReporter) Fprint(w io.Writer) {
+	report := struct {
+		Robots []RobotReport `json:"robots"`
+	}{
+		Robots: make([]RobotReport, 0, len(r.Explorer.Robots)),
+	}
+	for i := range r.Explorer.Robots {
+		report.Robots = append(report.Robots, r.Explorer.Robots[i].Report())
+	}
+
+	// can't fail, it is only made of plain values
+	out, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Fprintln(w, string(out))
+}
+
+// Report returns the structured report of the robot status
+func (r *Robot) Report() RobotReport {
+	rr := RobotReport{
+		ID:        r.ID,
+		Type:      r.EffectiveType(),
+		X:         r.PosX,
+		Y:         r.PosY,
+		Direction: r.Direction,
+		Status:    StatusOperating,
+		Loss:      r.Loss,
+	}
+
+	if r.isLost() {
+		rr.Status = StatusLost
+	} else if r.Depleted {
+		rr.Status = StatusDepleted
+	}
+
+	if r.Battery != nil {
+		level := r.Battery.Level
+		rr.Energy = &level
+	}
+
+	return rr
+}
@@ -44,12 +44,28 @@ func TestReporter_Print(t *testing.T) {
 						PosY:      3,
 						Direction: "W",
 					},
+					{
+						ID:        "scout-1",
+						Type:      RobotTypeHover,
+						PosX:      1,
+						PosY:      1,
+						Direction: "N",
+						Lost:      true,
+					},
+					{
+						PosX:      1,
+						PosY:      2,
+						Direction: "S",
+						Depleted:  true,
+					},
 				},
 			}},
 			want: `3 1 S
 0 3 E
 4 1 N LOST
 2 3 W
+1 1 N LOST id=scout-1 type=hover
+1 2 S DEPLETED
 `,
 		},
 	}
@@ -76,3 +92,91 @@ func TestReporter_Print(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONReporter_Print(t *testing.T) {
+	explorer := &MarsExplorer{
+		Surface: &Surface{MaxX: 4, MaxY: 4},
+		Robots: []Robot{
+			{PosX: 3, PosY: 1, Direction: "S"},
+			{
+				ID:        "scout-1",
+				Type:      RobotTypeHover,
+				PosX:      4,
+				PosY:      1,
+				Direction: "E",
+				Lost:      true,
+				Loss:      &Loss{Cause: LossCauseEdge, Instruction: 2, Target: Point{X: 5, Y: 1}},
+				Battery:   &Battery{Level: 3},
+			},
+			{
+				PosX:      2,
+				PosY:      2,
+				Direction: "N",
+				Lost:      true,
+				Loss:      &Loss{Cause: LossCauseCollision, Instruction: 0, Target: Point{X: 2, Y: 3}},
+			},
+		},
+	}
+	want := `{
+  "robots": [
+    {
+      "type": "wheeled",
+      "x": 3,
+      "y": 1,
+      "direction": "S",
+      "status": "operating"
+    },
+    {
+      "id": "scout-1",
+      "type": "hover",
+      "x": 4,
+      "y": 1,
+      "direction": "E",
+      "status": "lost",
+      "energy": 3,
+      "loss": {
+        "cause": "edge",
+        "instruction": 2,
+        "target": {
+          "x": 5,
+          "y": 1
+        }
+      }
+    },
+    {
+      "type": "wheeled",
+      "x": 2,
+      "y": 2,
+      "direction": "N",
+      "status": "lost",
+      "loss": {
+        "cause": "collision",
+        "instruction": 0,
+        "target": {
+          "x": 2,
+          "y": 3
+        }
+      }
+    }
+  ]
+}
+`
+
+	rescueStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	rep, err := NewReporter(FormatJSON, explorer)
+	if err != nil {
+		t.Fatalf("NewReporter() error = %v", err)
+	}
+	rep.Print()
+
+	w.Close()
+	out, _ := ioutil.ReadAll(r)
+	os.Stdout = rescueStdout
+
+	if want != string(out) {
+		t.Errorf("Print() got %s, want %s", out, want)
+	}
+}
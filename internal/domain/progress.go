@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrStepBudgetExhausted is returned once a mission executed as many instructions as its step budget allows
+var ErrStepBudgetExhausted = errors.New("step budget exhausted")
+
+// Progress is how far through its instructions a mission got
+type Progress struct {
+	// Steps is the number of instructions executed, a conditional counting as a single instruction
+	Steps int
+	// Finished are the indexes of the robots done with their instructions, lost ones and ones starting off the grid included
+	Finished []int
+	// Running are the indexes of the robots with instructions left to execute
+	Running []int
+	// Last is the last instruction executed, nil when none was, its events aren't recorded
+	Last *Step
+}
+
+// SendInstructionsContext explores the surface like SendInstructions, stopping cleanly after the current instruction
+// as soon as the context is done or budget instructions were executed (budget <= 0 meaning no limit)
+// the mission is left as it was when stopping, its progress being returned along with ctx.Err()
+// or ErrStepBudgetExhausted when interrupted
+func (m *MarsExplorer) SendInstructionsContext(ctx context.Context, budget int) (*Progress, error) {
+	p := &Progress{}
+	var err error
+
+	last, before := -1, Pose{}
+explore:
+	for r := range m.Robots {
+		if m.isRobotOffBound(m.Robots[r]) {
+			continue
+		}
+
+		for !m.Robots[r].Done() {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				break explore
+			default:
+			}
+			if budget > 0 && p.Steps >= budget {
+				err = ErrStepBudgetExhausted
+				break explore
+			}
+
+			last, before = r, m.Robots[r].Pose()
+			m.deployed(r)
+			m.step(r)
+			p.Steps++
+		}
+	}
+
+	for r := range m.Robots {
+		if m.Robots[r].Done() || m.isRobotOffBound(m.Robots[r]) {
+			p.Finished = append(p.Finished, r)
+		} else {
+			p.Running = append(p.Running, r)
+		}
+	}
+
+	if last >= 0 {
+		robot := &m.Robots[last]
+		index := robot.Cursor - 1
+		p.Last = &Step{
+			Number:      p.Steps,
+			Robot:       last,
+			Label:       robot.Label(last),
+			Instruction: index,
+			Command:     robot.Instructions[index],
+			Before:      before,
+			After:       robot.Pose(),
+		}
+	}
+
+	return p, err
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestMarsExplorer_SendInstructionsContext(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		budget  int
+		want    *Progress
+		wantErr error
+	}{
+		{
+			name: "no limit",
+			ctx:  context.Background(),
+			want: &Progress{
+				Steps:    26,
+				Finished: []int{0, 1, 2},
+				Last: &Step{Number: 26, Robot: 2, Label: "#3", Instruction: 9, Command: "L",
+					Before: Pose{X: 2, Y: 3, Direction: "W"}, After: Pose{X: 2, Y: 3, Direction: "S"}},
+			},
+		},
+		{
+			name:   "budget large enough",
+			ctx:    context.Background(),
+			budget: 26,
+			want: &Progress{
+				Steps:    26,
+				Finished: []int{0, 1, 2},
+				Last: &Step{Number: 26, Robot: 2, Label: "#3", Instruction: 9, Command: "L",
+					Before: Pose{X: 2, Y: 3, Direction: "W"}, After: Pose{X: 2, Y: 3, Direction: "S"}},
+			},
+		},
+		{
+			name:   "budget exhausted",
+			ctx:    context.Background(),
+			budget: 10,
+			want: &Progress{
+				Steps:    10,
+				Finished: []int{0},
+				Running:  []int{1, 2},
+				Last: &Step{Number: 10, Robot: 1, Label: "#2", Instruction: 1, Command: "R",
+					Before: Pose{X: 3, Y: 3, Direction: "N"}, After: Pose{X: 3, Y: 3, Direction: "E"}},
+			},
+			wantErr: ErrStepBudgetExhausted,
+		},
+		{
+			name: "context done",
+			ctx:  cancelled,
+			want: &Progress{
+				Running: []int{0, 1, 2},
+			},
+			wantErr: context.Canceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := sampleMission()
+
+			got, err := m.SendInstructionsContext(tt.ctx, tt.budget)
+			if err != tt.wantErr {
+				t.Errorf("SendInstructionsContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SendInstructionsContext() got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarsExplorer_SendInstructionsContext_Resume(t *testing.T) {
+	m := sampleMission()
+	for {
+		_, err := m.SendInstructionsContext(context.Background(), 3)
+		if err == nil {
+			break
+		}
+	}
+
+	want := sampleMission()
+	want.SendInstructions()
+	if !reflect.DeepEqual(m.Robots, want.Robots) || !reflect.DeepEqual(m.Scents, want.Scents) {
+		t.Errorf("SendInstructionsContext() got %v, want %v", m, want)
+	}
+}
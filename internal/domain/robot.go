@@ -6,6 +6,7 @@ const (
 	CommandRight   = "R"
 	CommandLeft    = "L"
 	CommandForward = "F"
+	CommandUTurn   = "U"
 
 	DirectionNorth = "N"
 	DirectionEast  = "E"
@@ -15,11 +16,22 @@ const (
 
 // Robot representation of a robot
 type Robot struct {
+	// ID is optional, unique within a mission when given
+	ID string
+	// Type is one of RobotTypes, wheeled when empty
+	Type         string
 	PosX         int
 	PosY         int
 	Direction    string
 	Instructions []string
-	Lost         bool
+	// Cursor is the index of the next instruction to execute
+	Cursor int
+	Lost   bool
+	// Loss records why and where the robot stopped operating, set for lost and depleted robots
+	Loss *Loss
+	// Battery is optional, a robot without one is never depleted
+	Battery  *Battery
+	Depleted bool
 }
 
 // ControlledRobot available commands to execute on a Robot
@@ -32,7 +44,20 @@ type ControlledRobot interface {
 }
 
 // Execute will execute the corresponding command on a robot if the instruction is recognised
+// the command energy cost is drained from the robot battery, a depleted robot can't execute any command
 func (r *Robot) Execute(c string) error {
+	if r.Depleted {
+		return fmt.Errorf("robot is depleted, can't execute %s", c)
+	}
+
+	if !r.Supports(c) {
+		return fmt.Errorf("unsupported command for %s robots: %s", r.typeName(), c)
+	}
+
+	if err := r.drain(c); err != nil {
+		return err
+	}
+
 	switch c {
 	case CommandRight:
 		return r.turnRight()
@@ -40,6 +65,11 @@ func (r *Robot) Execute(c string) error {
 		return r.turnLeft()
 	case CommandForward:
 		return r.forward()
+	case CommandUTurn:
+		if err := r.turnRight(); err != nil {
+			return err
+		}
+		return r.turnRight()
 	default:
 		return fmt.Errorf("unsupported command: %s", c)
 	}
@@ -130,23 +160,62 @@ func (r *Robot) backward() error {
 	}
 }
 
+// ahead returns the grid point the robot would reach moving forward
+// it returns false if the robot has an unrecognised direction
+func (r *Robot) ahead() (int, int, bool) {
+	next := *r
+	if err := next.forward(); err != nil {
+		return 0, 0, false
+	}
+
+	return next.PosX, next.PosY, true
+}
+
 // lost marks a robot as lost (used when gone out of the grid)
-func (r *Robot) lost() {
+func (r *Robot) lost(loss *Loss) {
 	r.Lost = true
+	r.Loss = loss
 	// panic / err up
 	_ = r.backward()
 }
 
+// Done tells if the robot has nothing left to execute, either because it went through its instructions or it stopped operating
+func (r *Robot) Done() bool {
+	return r.Lost || r.Depleted || r.Cursor >= len(r.Instructions)
+}
+
 // isLost returns the status of a Robot
 func (r *Robot) isLost() bool {
 	return r.Lost
 }
 
+// Label returns the robot ID or its position within the mission (starting at 1) when it has none
+func (r *Robot) Label(index int) string {
+	if r.ID != "" {
+		return r.ID
+	}
+
+	return fmt.Sprintf("#%d", index+1)
+}
+
 // ToString returns a pre-defined output as a string for a report of the robot status
+// the robot id and type are only given when declared, ie: "1 1 E LOST id=scout-1 type=hover"
 func (r *Robot) ToString() string {
+	s := fmt.Sprintf("%d %d %s", r.PosX, r.PosY, r.Direction)
+
 	if r.isLost() {
-		return fmt.Sprintf("%d %d %s %s", r.PosX, r.PosY, r.Direction, "LOST")
+		s = fmt.Sprintf("%s %s", s, "LOST")
+	} else if r.Depleted {
+		s = fmt.Sprintf("%s %s", s, "DEPLETED")
+	}
+
+	if r.ID != "" {
+		s = fmt.Sprintf("%s id=%s", s, r.ID)
+	}
+
+	if r.Type != "" {
+		s = fmt.Sprintf("%s type=%s", s, r.Type)
 	}
 
-	return fmt.Sprintf("%d %d %s", r.PosX, r.PosY, r.Direction)
+	return s
 }
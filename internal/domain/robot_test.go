@@ -96,3 +96,31 @@ func TestRobot_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestRobot_Label(t *testing.T) {
+	tests := []struct {
+		name  string
+		robot Robot
+		index int
+		want  string
+	}{
+		{
+			name:  "robot with an id",
+			robot: Robot{ID: "scout-1"},
+			index: 3,
+			want:  "scout-1",
+		},
+		{
+			name:  "anonymous robot",
+			index: 3,
+			want:  "#4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.robot.Label(tt.index); got != tt.want {
+				t.Errorf("Robot.Label() got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	RobotTypeWheeled = "wheeled"
+	RobotTypeTracked = "tracked"
+	RobotTypeHover   = "hover"
+
+	TerrainRock   = "rock"
+	TerrainCrater = "crater"
+)
+
+// TerrainEffect is how a robot type handles a kind of terrain when moving onto it
+type TerrainEffect int
+
+const (
+	// TerrainPass the robot moves onto the grid point as usual
+	TerrainPass TerrainEffect = iota
+	// TerrainBlock the forward move is ignored, just like a robot scent
+	TerrainBlock
+	// TerrainFall the robot is lost, leaving a scent behind
+	TerrainFall
+)
+
+// Capabilities is the profile of a robot type
+type Capabilities struct {
+	Commands map[string]bool
+	// Speed is the number of grid points travelled per forward command
+	Speed   int
+	Terrain map[string]TerrainEffect
+}
+
+// RobotTypes lists the capabilities of every robot type of the fleet, wheeled being the default one
+var RobotTypes = map[string]Capabilities{
+	RobotTypeWheeled: {
+		Commands: map[string]bool{CommandLeft: true, CommandRight: true, CommandForward: true},
+		Speed:    1,
+		Terrain:  map[string]TerrainEffect{TerrainRock: TerrainBlock, TerrainCrater: TerrainFall},
+	},
+	RobotTypeTracked: {
+		Commands: map[string]bool{CommandLeft: true, CommandRight: true, CommandForward: true, CommandUTurn: true},
+		Speed:    1,
+		Terrain:  map[string]TerrainEffect{TerrainRock: TerrainPass, TerrainCrater: TerrainFall},
+	},
+	RobotTypeHover: {
+		Commands: map[string]bool{CommandLeft: true, CommandRight: true, CommandForward: true},
+		Speed:    2,
+		Terrain:  map[string]TerrainEffect{TerrainRock: TerrainPass, TerrainCrater: TerrainPass},
+	},
+}
+
+// Point is a grid coordinate
+type Point struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// Capabilities returns the profile of the robot type, a robot without type being a wheeled one
+func (r *Robot) Capabilities() Capabilities {
+	return RobotTypes[r.EffectiveType()]
+}
+
+// EffectiveType returns the robot type, RobotTypeWheeled when not declared
+func (r *Robot) EffectiveType() string {
+	if r.Type == "" {
+		return RobotTypeWheeled
+	}
+
+	return r.Type
+}
+
+// Supports tells if the robot type is able to execute a command
+func (r *Robot) Supports(c string) bool {
+	return r.Capabilities().Commands[c]
+}
+
+// ValidateInstructions makes sure every command, including the ones within conditionals, is supported by the robot type
+func (r *Robot) ValidateInstructions() error {
+	return r.validate(r.Instructions)
+}
+
+// validate walks through instructions and returns an error for the first unsupported command
+func (r *Robot) validate(instructions []string) error {
+	for i, c := range instructions {
+		if isConditional(c) {
+			cond, err := ParseConditional(c)
+			if err != nil {
+				return err
+			}
+			if err := r.validate(cond.Then); err != nil {
+				return err
+			}
+			if err := r.validate(cond.Else); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !r.Supports(c) {
+			return fmt.Errorf("command %s at index %d is not supported by %s robots", c, i, r.typeName())
+		}
+	}
+
+	return nil
+}
+
+// typeName returns the robot type name, defaulting to wheeled
+func (r *Robot) typeName() string {
+	if r.Type == "" {
+		return RobotTypeWheeled
+	}
+
+	return r.Type
+}
+
+// TerrainAt returns the kind of terrain of a grid point, empty for a flat one
+func (s *Surface) TerrainAt(x, y int) string {
+	return s.Terrain[Point{X: x, Y: y}]
+}
+
+// terrainEffect returns how a robot handles the terrain of a given grid point
+func (m *MarsExplorer) terrainEffect(r *Robot, x, y int) TerrainEffect {
+	t := m.Surface.TerrainAt(x, y)
+	if t == "" {
+		return TerrainPass
+	}
+
+	return r.Capabilities().Terrain[t]
+}
+
+// LoadTerrain reads terrain lines ("<kind> <x> <y>", ie: "rock 2 3") onto the surface
+// and returns the remaining lines which are not describing terrain
+func (mb *MarsBuilder) LoadTerrain(surface *Surface, lines []string) ([]string, error) {
+	remaining, err := mb.loadTerrain(surface, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := make([]string, 0, len(remaining))
+	for _, i := range remaining {
+		rest = append(rest, lines[i])
+	}
+
+	return rest, nil
+}
+
+// loadTerrain is LoadTerrain returning the indexes of the remaining lines within lines
+func (mb *MarsBuilder) loadTerrain(surface *Surface, lines []string) ([]int, error) {
+	remaining := make([]int, 0, len(lines))
+	for i, v := range lines {
+		l := strings.Split(v, " ")
+		if l[0] != TerrainRock && l[0] != TerrainCrater {
+			remaining = append(remaining, i)
+			continue
+		}
+
+		if len(l) != 3 {
+			return nil, fmt.Errorf("expected terrain as <kind> <x> <y>, got %s", v)
+		}
+
+		x, err := strconv.Atoi(l[1])
+		if err != nil {
+			mb.logger.Errorf(`failed to convert terrain X "%s" into integer, got %q`, l[1], err)
+			return nil, err
+		}
+		y, err := strconv.Atoi(l[2])
+		if err != nil {
+			mb.logger.Errorf(`failed to convert terrain Y "%s" into integer, got %q`, l[2], err)
+			return nil, err
+		}
+
+		if !surface.Contains(x, y) {
+			return nil, fmt.Errorf("terrain %s is outside of the surface", v)
+		}
+
+		if surface.Terrain == nil {
+			surface.Terrain = make(map[Point]string)
+		}
+		surface.Terrain[Point{X: x, Y: y}] = l[0]
+	}
+
+	return remaining, nil
+}
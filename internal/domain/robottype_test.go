@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestRobot_ValidateInstructions(t *testing.T) {
+	tests := []struct {
+		name         string
+		robotType    string
+		instructions []string
+		wantErr      bool
+	}{
+		{
+			name:         "default robot supports the basic commands",
+			instructions: []string{"L", "R", "F"},
+		},
+		{
+			name:         "tracked robot can u-turn",
+			robotType:    RobotTypeTracked,
+			instructions: []string{"U", "F"},
+		},
+		{
+			name:         "wheeled robot can't u-turn",
+			robotType:    RobotTypeWheeled,
+			instructions: []string{"F", "U"},
+			wantErr:      true,
+		},
+		{
+			name:         "hover robot can't u-turn within a conditional",
+			robotType:    RobotTypeHover,
+			instructions: []string{"F", "[O?L:[S?U]]"},
+			wantErr:      true,
+		},
+		{
+			name:         "unknown commands are rejected",
+			instructions: []string{"F", "X"},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Robot{Type: tt.robotType, Instructions: tt.instructions}
+			if err := r.ValidateInstructions(); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateInstructions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMarsExplorer_SendInstructions_RobotTypes(t *testing.T) {
+	terrain := map[Point]string{
+		{X: 1, Y: 0}: TerrainRock,
+		{X: 0, Y: 1}: TerrainCrater,
+	}
+	tests := []struct {
+		name  string
+		robot Robot
+		want  Robot
+	}{
+		{
+			name:  "wheeled robot is blocked by rocks",
+			robot: Robot{Direction: "E", Instructions: []string{"F", "L", "F"}},
+			want:  Robot{Direction: "N", Lost: true, Loss: &Loss{Cause: LossCauseCrater, Instruction: 2, Target: Point{X: 0, Y: 1}}, Instructions: []string{"F", "L", "F"}, Cursor: 3},
+		},
+		{
+			name:  "tracked robot crawls over rocks and u-turns",
+			robot: Robot{Type: RobotTypeTracked, Direction: "E", Instructions: []string{"F", "F", "U", "F"}},
+			want:  Robot{Type: RobotTypeTracked, PosX: 1, Direction: "W", Instructions: []string{"F", "F", "U", "F"}, Cursor: 4},
+		},
+		{
+			name:  "hover robot flies over craters two grid points at a time",
+			robot: Robot{Type: RobotTypeHover, Direction: "N", Instructions: []string{"F", "R", "F"}},
+			want:  Robot{Type: RobotTypeHover, PosX: 2, PosY: 2, Direction: "E", Instructions: []string{"F", "R", "F"}, Cursor: 3},
+		},
+		{
+			name:  "hover robot gets lost on its second step",
+			robot: Robot{Type: RobotTypeHover, Direction: "N", Instructions: []string{"F", "F", "R"}},
+			want:  Robot{Type: RobotTypeHover, PosY: 3, Direction: "N", Lost: true, Loss: &Loss{Cause: LossCauseEdge, Instruction: 1, Target: Point{X: 0, Y: 4}}, Instructions: []string{"F", "F", "R"}, Cursor: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MarsExplorer{
+				Surface: &Surface{MaxX: 3, MaxY: 3, Terrain: terrain},
+				Robots:  []Robot{tt.robot},
+			}
+
+			m.SendInstructions()
+
+			if !reflect.DeepEqual(m.Robots[0], tt.want) {
+				t.Errorf("SendInstructions() got %v, want %v", m.Robots[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestMarsBuilder_LoadTerrain(t *testing.T) {
+	tests := []struct {
+		name        string
+		lines       []string
+		wantTerrain map[Point]string
+		wantLines   []string
+		wantErr     bool
+	}{
+		{
+			name:        "terrain lines are loaded onto the surface",
+			lines:       []string{"rock 1 2", "crater 0 0", "1 1 E", "RF"},
+			wantTerrain: map[Point]string{{X: 1, Y: 2}: TerrainRock, {X: 0, Y: 0}: TerrainCrater},
+			wantLines:   []string{"1 1 E", "RF"},
+		},
+		{
+			name:      "no terrain",
+			lines:     []string{"1 1 E", "RF"},
+			wantLines: []string{"1 1 E", "RF"},
+		},
+		{
+			name:    "terrain outside of the surface",
+			lines:   []string{"rock 9 2"},
+			wantErr: true,
+		},
+		{
+			name:    "malformed terrain",
+			lines:   []string{"rock 1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := logrus.New()
+			l.SetOutput(ioutil.Discard)
+
+			mb := &MarsBuilder{
+				logger: NewLogrusLogger(l),
+			}
+			s := &Surface{MaxX: 3, MaxY: 3}
+			got, err := mb.LoadTerrain(s, tt.lines)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadTerrain() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.wantLines) {
+				t.Errorf("LoadTerrain() got = %v, want %v", got, tt.wantLines)
+			}
+			if !reflect.DeepEqual(s.Terrain, tt.wantTerrain) {
+				t.Errorf("LoadTerrain() got terrain = %v, want %v", s.Terrain, tt.wantTerrain)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "fmt"
+
+const (
+	// EdgeLost makes a robot moving off the grid lost, the default
+	EdgeLost = "lost"
+	// EdgeStop makes a robot ignore any forward move off the grid
+	EdgeStop = "stop"
+
+	// ScentPose ignores a forward move from the very pose a robot got lost from, the default
+	ScentPose = "pose"
+	// ScentCell ignores any forward move making a robot lost from a grid point a robot got lost from, whatever its direction
+	ScentCell = "cell"
+	// ScentOff never leaves any scent
+	ScentOff = "off"
+)
+
+// Rules are the policies of a mission, the zero value being the default ones
+type Rules struct {
+	// Edge is what happens to a robot moving off the grid, EdgeLost when empty
+	Edge string `json:"edge,omitempty"`
+	// Scent is how scents are left and honoured, ScentPose when empty
+	Scent string `json:"scent,omitempty"`
+}
+
+// Validate returns an error for unsupported policies
+func (r Rules) Validate() error {
+	switch r.Edge {
+	case "", EdgeLost, EdgeStop:
+	default:
+		return fmt.Errorf("unsupported edge policy %s, expected %s or %s", r.Edge, EdgeLost, EdgeStop)
+	}
+
+	switch r.Scent {
+	case "", ScentPose, ScentCell, ScentOff:
+	default:
+		return fmt.Errorf("unsupported scent mode %s, expected %s, %s or %s", r.Scent, ScentPose, ScentCell, ScentOff)
+	}
+
+	return nil
+}
+
+// scentKey returns the scent a robot at a grid point facing a direction is guarded by, given the scent mode
+func (r Rules) scentKey(x, y int, direction string) Scent {
+	if r.Scent == ScentCell {
+		direction = ""
+	}
+
+	return Scent{PosX: x, PosY: y, Direction: direction}
+}
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMarsExplorer_SendInstructions_Rules(t *testing.T) {
+	tests := []struct {
+		name       string
+		rules      Rules
+		robots     []Robot
+		want       []Robot
+		wantScents []Scent
+	}{
+		{
+			name:  "robots stop at the edge",
+			rules: Rules{Edge: EdgeStop},
+			robots: []Robot{
+				{PosX: 3, PosY: 2, Direction: "N", Instructions: []string{"F", "F", "F", "R"}},
+			},
+			want: []Robot{
+				{PosX: 3, PosY: 3, Direction: "E", Instructions: []string{"F", "F", "F", "R"}, Cursor: 4},
+			},
+		},
+		{
+			name:  "scents guard a grid point whatever the direction",
+			rules: Rules{Scent: ScentCell},
+			robots: []Robot{
+				{PosX: 5, PosY: 2, Direction: "N", Instructions: []string{"F", "R", "F"}},
+				{PosX: 5, PosY: 2, Direction: "N", Instructions: []string{"F", "R", "F", "L", "F", "L", "F"}},
+			},
+			want: []Robot{
+				{PosX: 5, PosY: 3, Direction: "E", Instructions: []string{"F", "R", "F"}, Cursor: 3, Lost: true,
+					Loss: &Loss{Cause: LossCauseEdge, Instruction: 2, Target: Point{X: 6, Y: 3}}},
+				{PosX: 4, PosY: 3, Direction: "W", Instructions: []string{"F", "R", "F", "L", "F", "L", "F"}, Cursor: 7},
+			},
+			wantScents: []Scent{{PosX: 5, PosY: 3, Direction: "E"}},
+		},
+		{
+			name:  "scents are off",
+			rules: Rules{Scent: ScentOff},
+			robots: []Robot{
+				{PosX: 3, PosY: 3, Direction: "N", Instructions: []string{"F"}},
+				{PosX: 3, PosY: 3, Direction: "N", Instructions: []string{"F"}},
+			},
+			want: []Robot{
+				{PosX: 3, PosY: 3, Direction: "N", Instructions: []string{"F"}, Cursor: 1, Lost: true,
+					Loss: &Loss{Cause: LossCauseEdge, Instruction: 0, Target: Point{X: 3, Y: 4}}},
+				{PosX: 3, PosY: 3, Direction: "N", Instructions: []string{"F"}, Cursor: 1, Lost: true,
+					Loss: &Loss{Cause: LossCauseEdge, Instruction: 0, Target: Point{X: 3, Y: 4}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MarsExplorer{Surface: &Surface{MaxX: 5, MaxY: 3}, Robots: tt.robots, Rules: tt.rules}
+			parallel := m.Copy()
+
+			m.SendInstructions()
+			parallel.SendInstructionsParallel(2)
+
+			if !reflect.DeepEqual(m.Robots, tt.want) {
+				t.Errorf("SendInstructions() got %v, want %v", m.Robots, tt.want)
+			}
+			if !reflect.DeepEqual(m.Scents, tt.wantScents) {
+				t.Errorf("SendInstructions() got scents %v, want %v", m.Scents, tt.wantScents)
+			}
+			if !reflect.DeepEqual(parallel.Robots, m.Robots) || !reflect.DeepEqual(parallel.Scents, m.Scents) {
+				t.Errorf("SendInstructionsParallel() got %v, want %v", parallel, m)
+			}
+		})
+	}
+}
+
+func TestRules_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   Rules
+		wantErr bool
+	}{
+		{name: "default rules", rules: Rules{}},
+		{name: "supported rules", rules: Rules{Edge: EdgeStop, Scent: ScentCell}},
+		{name: "unsupported edge policy", rules: Rules{Edge: "wrap"}, wantErr: true},
+		{name: "unsupported scent mode", rules: Rules{Scent: "forever"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rules.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
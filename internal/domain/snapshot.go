@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SnapshotVersion is the version of the snapshot format written by MarsExplorer.Snapshot
+const SnapshotVersion = 1
+
+// Snapshot is the versioned JSON representation of the full state of a MarsExplorer
+type Snapshot struct {
+	Version int             `json:"version"`
+	Surface SurfaceSnapshot `json:"surface"`
+	Robots  []RobotSnapshot `json:"robots"`
+	Scents  []ScentSnapshot `json:"scents"`
+	// Rules are the policies of the mission, omitted when the default ones
+	Rules *Rules `json:"rules,omitempty"`
+}
+
+// SurfaceSnapshot is the state of the Surface
+type SurfaceSnapshot struct {
+	MaxX    int               `json:"max_x"`
+	MaxY    int               `json:"max_y"`
+	Terrain []TerrainSnapshot `json:"terrain,omitempty"`
+}
+
+// TerrainSnapshot is a grid point of the surface which isn't flat
+type TerrainSnapshot struct {
+	Kind string `json:"kind"`
+	X    int    `json:"x"`
+	Y    int    `json:"y"`
+}
+
+// RobotSnapshot is the state of a Robot, including how far through its instructions it has got
+type RobotSnapshot struct {
+	ID           string      `json:"id,omitempty"`
+	Type         string      `json:"type,omitempty"`
+	X            int         `json:"x"`
+	Y            int         `json:"y"`
+	Direction    string      `json:"direction"`
+	Instructions string      `json:"instructions"`
+	Cursor       int         `json:"cursor"`
+	Lost         bool        `json:"lost,omitempty"`
+	Depleted     bool        `json:"depleted,omitempty"`
+	Energy       *int        `json:"energy,omitempty"`
+	EnergyCosts  EnergyCosts `json:"energy_costs,omitempty"`
+	Loss         *Loss       `json:"loss,omitempty"`
+}
+
+// ScentSnapshot is a Scent
+type ScentSnapshot struct {
+	X         int    `json:"x"`
+	Y         int    `json:"y"`
+	Direction string `json:"direction"`
+}
+
+// Snapshot returns the JSON snapshot of the MarsExplorer state which can be restored with Restore, even mid-mission
+func (m *MarsExplorer) Snapshot() ([]byte, error) {
+	s := Snapshot{
+		Version: SnapshotVersion,
+		Surface: SurfaceSnapshot{MaxX: m.Surface.MaxX, MaxY: m.Surface.MaxY},
+		Robots:  make([]RobotSnapshot, 0, len(m.Robots)),
+		Scents:  make([]ScentSnapshot, 0, len(m.Scents)),
+	}
+
+	if m.Rules != (Rules{}) {
+		rules := m.Rules
+		s.Rules = &rules
+	}
+
+	for p, kind := range m.Surface.Terrain {
+		s.Surface.Terrain = append(s.Surface.Terrain, TerrainSnapshot{Kind: kind, X: p.X, Y: p.Y})
+	}
+	sort.Slice(s.Surface.Terrain, func(i, j int) bool {
+		if s.Surface.Terrain[i].Y != s.Surface.Terrain[j].Y {
+			return s.Surface.Terrain[i].Y < s.Surface.Terrain[j].Y
+		}
+		return s.Surface.Terrain[i].X < s.Surface.Terrain[j].X
+	})
+
+	for _, r := range m.Robots {
+		rs := RobotSnapshot{
+			ID:           r.ID,
+			Type:         r.Type,
+			X:            r.PosX,
+			Y:            r.PosY,
+			Direction:    r.Direction,
+			Instructions: strings.Join(r.Instructions, ""),
+			Cursor:       r.Cursor,
+			Lost:         r.Lost,
+			Depleted:     r.Depleted,
+			Loss:         r.Loss,
+		}
+		if r.Battery != nil {
+			level := r.Battery.Level
+			rs.Energy = &level
+			rs.EnergyCosts = r.Battery.Costs
+		}
+		s.Robots = append(s.Robots, rs)
+	}
+
+	for _, sc := range m.Scents {
+		s.Scents = append(s.Scents, ScentSnapshot{X: sc.PosX, Y: sc.PosY, Direction: sc.Direction})
+	}
+
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// Restore returns the MarsExplorer captured by a JSON snapshot, SendInstructions carrying on from where it was
+func Restore(data []byte) (*MarsExplorer, error) {
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot, got %q", err)
+	}
+
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", s.Version, SnapshotVersion)
+	}
+
+	m := &MarsExplorer{
+		Surface: &Surface{MaxX: s.Surface.MaxX, MaxY: s.Surface.MaxY},
+		Robots:  make([]Robot, 0, len(s.Robots)),
+	}
+
+	if s.Rules != nil {
+		if err := s.Rules.Validate(); err != nil {
+			return nil, err
+		}
+		m.Rules = *s.Rules
+	}
+
+	for _, t := range s.Surface.Terrain {
+		if t.Kind != TerrainRock && t.Kind != TerrainCrater {
+			return nil, fmt.Errorf("unsupported terrain %s", t.Kind)
+		}
+		if m.Surface.Terrain == nil {
+			m.Surface.Terrain = make(map[Point]string)
+		}
+		m.Surface.Terrain[Point{X: t.X, Y: t.Y}] = t.Kind
+	}
+
+	ids := make(map[string]struct{})
+	for i, rs := range s.Robots {
+		if _, ok := RobotTypes[rs.Type]; rs.Type != "" && !ok {
+			return nil, fmt.Errorf("robot #%d: unsupported robot type %s", i+1, rs.Type)
+		}
+		if _, ok := ids[rs.ID]; rs.ID != "" && ok {
+			return nil, fmt.Errorf("duplicate robot id %s", rs.ID)
+		}
+		ids[rs.ID] = struct{}{}
+
+		instructions, err := TokenizeInstructions(rs.Instructions)
+		if err != nil {
+			return nil, fmt.Errorf("robot #%d: %s", i+1, err)
+		}
+		if rs.Cursor < 0 || rs.Cursor > len(instructions) {
+			return nil, fmt.Errorf("robot #%d: cursor %d out of its %d instructions", i+1, rs.Cursor, len(instructions))
+		}
+
+		r := Robot{
+			ID:           rs.ID,
+			Type:         rs.Type,
+			PosX:         rs.X,
+			PosY:         rs.Y,
+			Direction:    rs.Direction,
+			Instructions: instructions,
+			Cursor:       rs.Cursor,
+			Lost:         rs.Lost,
+			Depleted:     rs.Depleted,
+			Loss:         rs.Loss,
+		}
+		if rs.Energy != nil {
+			r.Battery = &Battery{Level: *rs.Energy, Costs: rs.EnergyCosts}
+		}
+		m.Robots = append(m.Robots, r)
+	}
+
+	for _, sc := range s.Scents {
+		m.Scents = append(m.Scents, Scent{PosX: sc.X, PosY: sc.Y, Direction: sc.Direction})
+	}
+
+	return m, nil
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestMarsExplorer_Snapshot(t *testing.T) {
+	m := &MarsExplorer{
+		Surface: &Surface{MaxX: 5, MaxY: 3, Terrain: map[Point]string{{X: 5, Y: 0}: TerrainRock, {X: 0, Y: 0}: TerrainCrater}},
+		Robots: []Robot{
+			{PosX: 1, PosY: 1, Direction: "E", Instructions: []string{"R", "F", "R", "F", "R", "F", "R", "F"}},
+			{ID: "scout-1", PosX: 3, PosY: 2, Direction: "N", Instructions: []string{"F", "R", "R", "F", "L", "L", "F", "F", "R", "R", "F", "L", "L"}},
+			{Type: RobotTypeTracked, PosX: 0, PosY: 3, Direction: "W", Instructions: []string{"U", "F", "F", "[S?L:F]", "L", "F", "L", "F", "L"}, Battery: &Battery{Level: 30, Costs: EnergyCosts{"F": 3, "L": 1, "R": 1, "U": 2}}},
+		},
+	}
+
+	// explore the mission up to the middle of the second robot
+	for !m.Robots[0].Done() {
+		m.step(0)
+	}
+	for i := 0; i < 8; i++ {
+		m.step(1)
+	}
+
+	data, err := m.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	restored, err := Restore(data)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+
+	again, err := restored.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Errorf("Restore() got snapshot %s, want %s", again, data)
+	}
+
+	// resuming the restored mission ends up exactly like the original one
+	m.SendInstructions()
+	restored.SendInstructions()
+	if !reflect.DeepEqual(restored.Robots, m.Robots) {
+		t.Errorf("Restore() resumed robots %v, want %v", restored.Robots, m.Robots)
+	}
+	if !reflect.DeepEqual(restored.Scents, m.Scents) {
+		t.Errorf("Restore() resumed scents %v, want %v", restored.Scents, m.Scents)
+	}
+}
+
+func TestRestore(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "valid snapshot",
+			data: `{"version": 1, "surface": {"max_x": 5, "max_y": 3}, "robots": [{"x": 1, "y": 1, "direction": "E", "instructions": "RF", "cursor": 1}], "scents": [{"x": 3, "y": 3, "direction": "N"}]}`,
+		},
+		{
+			name:    "unsupported version",
+			data:    `{"version": 2, "surface": {"max_x": 5, "max_y": 3}}`,
+			wantErr: true,
+		},
+		{
+			name:    "cursor out of the instructions",
+			data:    `{"version": 1, "surface": {"max_x": 5, "max_y": 3}, "robots": [{"x": 1, "y": 1, "direction": "E", "instructions": "RF", "cursor": 3}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid instructions",
+			data:    `{"version": 1, "surface": {"max_x": 5, "max_y": 3}, "robots": [{"x": 1, "y": 1, "direction": "E", "instructions": "R[F"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported terrain",
+			data:    `{"version": 1, "surface": {"max_x": 5, "max_y": 3, "terrain": [{"kind": "lava", "x": 1, "y": 1}]}}`,
+			wantErr: true,
+		},
+		{
+			name:    "not a snapshot",
+			data:    `5 3`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Restore([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Restore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+const (
+	StreamMove            = "move"
+	StreamScentBlocked    = "scent-blocked"
+	StreamTerrainBlocked  = "terrain-blocked"
+	StreamEdgeBlocked     = "edge-blocked"
+	StreamLost            = "lost"
+	StreamMissionComplete = "mission-complete"
+	StreamReset           = "reset"
+)
+
+// maxStreamEvents is the number of events kept per mission for the clients to read
+const maxStreamEvents = 4096
+
+// StreamEvent is an event of a mission as sent to the clients of GET /missions/{id}/events
+// ID is the event position within the mission, starting at 1
+type StreamEvent struct {
+	ID   int
+	Type string
+	Data interface{}
+}
+
+// MoveData is the data of a move event
+type MoveData struct {
+	Robot       string      `json:"robot"`
+	Instruction int         `json:"instruction"`
+	Command     string      `json:"command"`
+	From        domain.Pose `json:"from"`
+	To          domain.Pose `json:"to"`
+}
+
+// BlockedData is the data of a scent-blocked, terrain-blocked or edge-blocked event
+type BlockedData struct {
+	Robot       string      `json:"robot"`
+	Instruction int         `json:"instruction"`
+	Command     string      `json:"command"`
+	Pose        domain.Pose `json:"pose"`
+}
+
+// ResetData is the data of a reset event, sent in place of the events dropped before a client could read them
+// the client then resumes with the oldest event kept
+type ResetData struct {
+	Missed int `json:"missed"`
+}
+
+// LostData is the data of a lost event
+type LostData struct {
+	Robot string      `json:"robot"`
+	Pose  domain.Pose `json:"pose"`
+	Loss  domain.Loss `json:"loss"`
+}
+
+// eventLog keeps the last events of a mission in a ring buffer so that clients read them at their own pace,
+// resuming where they left off
+// appending never blocks on clients, a slow client only falling behind and getting a reset event once
+// the events it didn't read yet are overwritten
+type eventLog struct {
+	mu     sync.Mutex
+	events []StreamEvent
+	// size is the number of events kept, events growing up to it before wrapping around
+	size    int
+	total   int
+	closed  bool
+	updated chan struct{}
+}
+
+// newEventLog returns an empty eventLog keeping the last size events
+func newEventLog(size int) *eventLog {
+	return &eventLog{size: size, updated: make(chan struct{})}
+}
+
+// append adds an event, overwriting the oldest one once the log is full and waking up the clients waiting for it
+func (l *eventLog) append(typ string, data interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := StreamEvent{ID: l.total + 1, Type: typ, Data: data}
+	if len(l.events) < l.size {
+		l.events = append(l.events, e)
+	} else {
+		l.events[l.total%l.size] = e
+	}
+	l.total++
+	close(l.updated)
+	l.updated = make(chan struct{})
+}
+
+// close marks the log as complete, no event being appended anymore
+func (l *eventLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+	close(l.updated)
+	l.updated = make(chan struct{})
+}
+
+// since returns the events following the event id, whether the log is complete
+// and a channel closed once there is anything new
+// the events are preceded by a reset event when some following the event id were already overwritten
+func (l *eventLog) since(id int) ([]StreamEvent, bool, <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if id < 0 {
+		id = 0
+	}
+	if id > l.total {
+		id = l.total
+	}
+
+	var events []StreamEvent
+	if oldest := l.total - len(l.events); id < oldest {
+		events = append(events, StreamEvent{ID: oldest, Type: StreamReset, Data: ResetData{Missed: oldest - id}})
+		id = oldest
+	}
+	for ; id < l.total; id++ {
+		events = append(events, l.events[id%l.size])
+	}
+
+	return events, l.closed, l.updated
+}
+
+// missionRecorder records the trajectories of the robots of a mission and sends its events to the mission event log
+type missionRecorder struct {
+	labels       []string
+	trajectories [][]domain.Pose
+	events       *eventLog
+}
+
+// Moved records the pose reached by a robot
+func (m *missionRecorder) Moved(e domain.MovedEvent) {
+	m.trajectories[e.Robot] = append(m.trajectories[e.Robot], e.To)
+	m.events.append(StreamMove, MoveData{Robot: m.labels[e.Robot], Instruction: e.Instruction, Command: e.Command, From: e.From, To: e.To})
+}
+
+// Blocked records a forward move ignored
+func (m *missionRecorder) Blocked(e domain.BlockedEvent) {
+	var typ string
+	switch e.By {
+	case domain.BlockedByTerrain:
+		typ = StreamTerrainBlocked
+	case domain.BlockedByEdge:
+		typ = StreamEdgeBlocked
+	default:
+		typ = StreamScentBlocked
+	}
+	m.events.append(typ, BlockedData{Robot: m.labels[e.Robot], Instruction: e.Instruction, Command: e.Command, Pose: e.Pose})
+}
+
+// Lost records a robot not operating anymore
+func (m *missionRecorder) Lost(e domain.LostEvent) {
+	m.events.append(StreamLost, LostData{Robot: m.labels[e.Robot], Pose: e.Pose, Loss: e.Loss})
+}
+
+// ScentLeft is part of the lost event
+func (m *missionRecorder) ScentLeft(domain.ScentLeftEvent) {}
+
+// getEvents streams the events of a mission as Server-Sent Events until it is complete
+// a client reconnecting with a Last-Event-ID header resumes after that event
+func (s *Server) getEvents(w http.ResponseWriter, r *http.Request, id string) {
+	m, ok := s.mission(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown mission %s", id))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Errorf("streaming not supported"))
+		return
+	}
+
+	last := 0
+	if h := r.Header.Get("Last-Event-ID"); h != "" {
+		n, err := strconv.Atoi(h)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid Last-Event-ID %q", h))
+			return
+		}
+		last = n
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		events, closed, updated := m.events.since(last)
+		for _, e := range events {
+			data, err := json.Marshal(e.Data)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data); err != nil {
+				return
+			}
+			last = e.ID
+		}
+		flusher.Flush()
+
+		if closed {
+			return
+		}
+
+		select {
+		case <-updated:
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
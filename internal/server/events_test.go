@@ -0,0 +1,233 @@
+package server
+
+import (
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServer_getEvents(t *testing.T) {
+	ts := newServer(t)
+	do(t, http.MethodPost, ts.URL+"/missions", "text/plain", "5 3\n3 3 N\nF\n\n3 3 N\nFR\n")
+
+	events := `id: 1
+event: move
+data: {"robot":"#1","instruction":0,"command":"F","from":{"x":3,"y":3,"direction":"N"},"to":{"x":3,"y":4,"direction":"N"}}
+
+id: 2
+event: lost
+data: {"robot":"#1","pose":{"x":3,"y":3,"direction":"N"},"loss":{"cause":"edge","instruction":0,"target":{"x":3,"y":4}}}
+
+id: 3
+event: scent-blocked
+data: {"robot":"#2","instruction":0,"command":"F","pose":{"x":3,"y":3,"direction":"N"}}
+
+id: 4
+event: move
+data: {"robot":"#2","instruction":1,"command":"R","from":{"x":3,"y":3,"direction":"N"},"to":{"x":3,"y":3,"direction":"E"}}
+
+id: 5
+event: mission-complete
+data: {"id":"1","planet":"mars","status":"complete","robots":[{"type":"wheeled","x":3,"y":3,"direction":"N","status":"lost","loss":{"cause":"edge","instruction":0,"target":{"x":3,"y":4}}},{"type":"wheeled","x":3,"y":3,"direction":"E","status":"operating"}]}`
+
+	tests := []struct {
+		name        string
+		path        string
+		lastEventID string
+		wantStatus  int
+		want        string
+	}{
+		{
+			name:       "every event",
+			path:       "/missions/1/events",
+			wantStatus: http.StatusOK,
+			want:       events,
+		},
+		{
+			name:        "resume after the last event received",
+			path:        "/missions/1/events",
+			lastEventID: "3",
+			wantStatus:  http.StatusOK,
+			want:        events[strings.Index(events, "id: 4"):],
+		},
+		{
+			name:        "invalid last event id",
+			path:        "/missions/1/events",
+			lastEventID: "two",
+			wantStatus:  http.StatusBadRequest,
+			want:        `{"error":"invalid Last-Event-ID \"two\""}`,
+		},
+		{
+			name:       "unknown mission",
+			path:       "/missions/2/events",
+			wantStatus: http.StatusNotFound,
+			want:       `{"error":"unknown mission 2"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, ts.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.lastEventID != "" {
+				req.Header.Set("Last-Event-ID", tt.lastEventID)
+			}
+
+			status, body := send(t, req)
+			if status != tt.wantStatus {
+				t.Errorf("GET %s got status %d, want %d", tt.path, status, tt.wantStatus)
+			}
+			if body != tt.want {
+				t.Errorf("GET %s got %s, want %s", tt.path, body, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_getEvents_running(t *testing.T) {
+	ts := newServer(t)
+
+	status, body := do(t, http.MethodPost, ts.URL+"/missions?wait=false", "text/plain", sample)
+	if status != http.StatusAccepted || !strings.HasPrefix(body, `{"id":"1","planet":"mars","status":`) {
+		t.Fatalf("POST /missions?wait=false got %d %s", status, body)
+	}
+
+	_, body = do(t, http.MethodGet, ts.URL+"/missions/1/events", "", "")
+	if strings.Count(body, "event: move") != 25 || strings.Count(body, "event: lost") != 1 ||
+		!strings.Contains(body, "id: 28\nevent: mission-complete\n") {
+		t.Errorf("GET /missions/1/events got %s", body)
+	}
+
+	_, body = do(t, http.MethodGet, ts.URL+"/missions/1", "", "")
+	if !strings.Contains(body, `"status":"complete"`) {
+		t.Errorf("GET /missions/1 got %s once the events streamed", body)
+	}
+}
+
+func TestServer_getEvents_edgeStop(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	builder := domain.NewMarsBuilder(nil)
+	builder.SetRules(domain.Rules{Edge: domain.EdgeStop})
+	ts := httptest.NewServer(New(builder, logger))
+	t.Cleanup(ts.Close)
+
+	do(t, http.MethodPost, ts.URL+"/missions", "text/plain", "5 3\n3 3 N\nF\n")
+
+	_, body := do(t, http.MethodGet, ts.URL+"/missions/1/events", "", "")
+	want := "id: 1\nevent: edge-blocked\n" +
+		`data: {"robot":"#1","instruction":0,"command":"F","pose":{"x":3,"y":3,"direction":"N"}}`
+	if !strings.HasPrefix(body, want) {
+		t.Errorf("GET /missions/1/events got %s, want it to start with %s", body, want)
+	}
+}
+
+func Test_eventLog(t *testing.T) {
+	l := newEventLog(maxStreamEvents)
+
+	// appending never waits for readers
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			l.append(StreamMove, i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("append() blocked without any reader")
+	}
+
+	events, closed, updated := l.since(998)
+	if len(events) != 2 || events[0].ID != 999 || closed {
+		t.Errorf("since() got %v, %t", events, closed)
+	}
+
+	l.close()
+	select {
+	case <-updated:
+	default:
+		t.Errorf("close() didn't wake up the readers")
+	}
+	if _, closed, _ := l.since(1000); !closed {
+		t.Errorf("since() expected the log to be closed")
+	}
+}
+
+func Test_eventLog_grows(t *testing.T) {
+	l := newEventLog(maxStreamEvents)
+	l.append(StreamMove, 0)
+	l.append(StreamMove, 1)
+
+	// a mission only sending a few events only keeps as many
+	if cap(l.events) >= maxStreamEvents {
+		t.Errorf("newEventLog() allocated %d events up front", cap(l.events))
+	}
+	if events, _, _ := l.since(0); len(events) != 2 || events[1].ID != 2 {
+		t.Errorf("since() got %v", events)
+	}
+}
+
+func Test_eventLog_overwritten(t *testing.T) {
+	l := newEventLog(3)
+	for i := 0; i < 5; i++ {
+		l.append(StreamMove, i)
+	}
+
+	tests := []struct {
+		name string
+		id   int
+		want []StreamEvent
+	}{
+		{
+			name: "every event",
+			id:   0,
+			want: []StreamEvent{
+				{ID: 2, Type: StreamReset, Data: ResetData{Missed: 2}},
+				{ID: 3, Type: StreamMove, Data: 2},
+				{ID: 4, Type: StreamMove, Data: 3},
+				{ID: 5, Type: StreamMove, Data: 4},
+			},
+		},
+		{
+			name: "resume after an overwritten event",
+			id:   1,
+			want: []StreamEvent{
+				{ID: 2, Type: StreamReset, Data: ResetData{Missed: 1}},
+				{ID: 3, Type: StreamMove, Data: 2},
+				{ID: 4, Type: StreamMove, Data: 3},
+				{ID: 5, Type: StreamMove, Data: 4},
+			},
+		},
+		{
+			name: "resume after the last event overwritten",
+			id:   2,
+			want: []StreamEvent{
+				{ID: 3, Type: StreamMove, Data: 2},
+				{ID: 4, Type: StreamMove, Data: 3},
+				{ID: 5, Type: StreamMove, Data: 4},
+			},
+		},
+		{
+			name: "nothing new",
+			id:   5,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _, _ := l.since(tt.id); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("since() got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// optionValue is the charset of the values written as key=value on a mission file line, ie: an id or a type
+var optionValue = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// MissionRequest is the JSON representation of a mission, the same as a mission file
+type MissionRequest struct {
+	Surface domain.SurfaceSnapshot `json:"surface"`
+	Robots  []RobotRequest         `json:"robots"`
+}
+
+// RobotRequest is a robot of a MissionRequest, instructions being written as in a mission file, ie: "FRF[O?L:F]"
+type RobotRequest struct {
+	ID           string `json:"id,omitempty"`
+	Type         string `json:"type,omitempty"`
+	X            int    `json:"x"`
+	Y            int    `json:"y"`
+	Direction    string `json:"direction"`
+	Instructions string `json:"instructions"`
+	Energy       *int   `json:"energy,omitempty"`
+}
+
+// Validate asserts every field is written the way Lines expects, a field must not be able to
+// add or break lines of the mission file, ie: an id with a space or instructions with a newline
+func (mr MissionRequest) Validate() error {
+	for i, t := range mr.Surface.Terrain {
+		if !optionValue.MatchString(t.Kind) {
+			return fmt.Errorf("terrain %d: invalid kind %q", i+1, t.Kind)
+		}
+	}
+
+	for i, r := range mr.Robots {
+		if r.ID != "" && !optionValue.MatchString(r.ID) {
+			return fmt.Errorf("robot #%d: invalid id %q", i+1, r.ID)
+		}
+		if r.Type != "" && !optionValue.MatchString(r.Type) {
+			return fmt.Errorf("robot #%d: invalid type %q", i+1, r.Type)
+		}
+		switch r.Direction {
+		case domain.DirectionNorth, domain.DirectionEast, domain.DirectionSouth, domain.DirectionWest:
+		default:
+			return fmt.Errorf("robot #%d: invalid direction %q", i+1, r.Direction)
+		}
+		switch {
+		case r.Instructions == "":
+			return fmt.Errorf("robot #%d: expected instructions", i+1)
+		case strings.IndexFunc(r.Instructions, unicode.IsSpace) >= 0:
+			return fmt.Errorf("robot #%d: instructions can't contain whitespace", i+1)
+		case strings.HasPrefix(r.Instructions, domain.CommentPrefix):
+			return fmt.Errorf("robot #%d: instructions can't start with %s", i+1, domain.CommentPrefix)
+		}
+	}
+
+	return nil
+}
+
+// Lines returns the request as the lines of a mission file, to be read by MarsBuilder.Build
+func (mr MissionRequest) Lines() []string {
+	lines := []string{fmt.Sprintf("%d %d", mr.Surface.MaxX, mr.Surface.MaxY)}
+	for _, t := range mr.Surface.Terrain {
+		lines = append(lines, fmt.Sprintf("%s %d %d", t.Kind, t.X, t.Y))
+	}
+
+	for _, r := range mr.Robots {
+		position := fmt.Sprintf("%d %d %s", r.X, r.Y, r.Direction)
+		if r.ID != "" {
+			position = fmt.Sprintf("%s id=%s", position, r.ID)
+		}
+		if r.Type != "" {
+			position = fmt.Sprintf("%s type=%s", position, r.Type)
+		}
+		if r.Energy != nil {
+			position = fmt.Sprintf("%s energy=%d", position, *r.Energy)
+		}
+		lines = append(lines, position, r.Instructions, "")
+	}
+
+	return lines
+}
+
+// readMission reads the lines of a mission from a request body, either a mission file or a JSON MissionRequest
+func readMission(contentType string, body io.Reader) ([]string, error) {
+	switch {
+	case strings.HasPrefix(contentType, "application/json"):
+		var mr MissionRequest
+		if err := json.NewDecoder(body).Decode(&mr); err != nil {
+			return nil, fmt.Errorf("invalid JSON mission, got %q", err)
+		}
+		if len(mr.Robots) == 0 {
+			return nil, fmt.Errorf("expected robots, received 0")
+		}
+		if err := mr.Validate(); err != nil {
+			return nil, err
+		}
+		return mr.Lines(), nil
+	case contentType == "" || strings.HasPrefix(contentType, "text/plain"):
+		// read line by line, the same way as a mission file
+		var lines []string
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("unable to read the mission, got %q", err)
+		}
+		return lines, nil
+	default:
+		return nil, errUnsupportedMediaType
+	}
+}
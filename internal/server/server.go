@@ -0,0 +1,380 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultPlanet is the planet a mission is sent to when none is given
+const DefaultPlanet = "mars"
+
+// maxMissionSize is the maximum size of a mission request body
+const maxMissionSize = 1 << 20
+
+// maxCompletedMissions is the number of complete missions kept, the oldest ones being evicted first
+const maxCompletedMissions = 1000
+
+var errUnsupportedMediaType = errors.New("unsupported content type, expected text/plain or application/json")
+
+const (
+	MissionRunning  = "running"
+	MissionComplete = "complete"
+)
+
+// Server is the HTTP API exploring missions sent by clients
+// scents are kept per planet, every mission sent to a planet starting with the scents left by the previous ones
+// only the last complete missions are kept, older ones being unknown afterwards (see maxCompletedMissions)
+//
+//	POST   /missions                  explore a mission (text/plain mission file or application/json MissionRequest),
+//	                                  the planet being given by the planet query parameter, with wait=false
+//	                                  the response being sent right away while the mission is explored
+//	GET    /missions/{id}             the mission status and the robots status once explored
+//	GET    /missions/{id}/trajectory  the poses of every robot, from its starting one, once explored
+//	GET    /missions/{id}/events      the events of the mission as Server-Sent Events (see StreamEvent)
+//	GET    /planets/{name}/scents     the scents left on a planet
+//	DELETE /planets/{name}/scents     remove the scents left on a planet
+//	GET    /metrics                   the statistics of the missions explored in the Prometheus text format
+type Server struct {
+	builder domain.MarsBuilder
+	logger  *logrus.Logger
+	metrics *domain.Metrics
+
+	// streamEvents is the number of events kept per mission (see eventLog)
+	streamEvents int
+	// retained is the number of complete missions kept
+	retained int
+
+	mu        sync.Mutex
+	missions  map[string]*Mission
+	completed []string
+	planets   map[string]*planet
+	next      int
+
+	running sync.WaitGroup
+	done    chan struct{}
+	close   sync.Once
+}
+
+// Mission is a mission sent to the server
+type Mission struct {
+	ID     string `json:"id"`
+	Planet string `json:"planet"`
+	// Status is MissionRunning until explored, MissionComplete then
+	Status string `json:"status"`
+	// Robots is the robots status once explored
+	Robots []domain.RobotReport `json:"robots,omitempty"`
+
+	trajectories [][]domain.Pose
+	labels       []string
+	events       *eventLog
+}
+
+// Trajectory is the poses of a robot throughout a mission
+type Trajectory struct {
+	Robot string        `json:"robot"`
+	Poses []domain.Pose `json:"poses"`
+}
+
+// planet holds the scents left by the missions sent to it, a mission being explored at a time
+type planet struct {
+	mu     sync.Mutex
+	scents []domain.Scent
+}
+
+// New returns a Server reading missions with the given MarsBuilder, its limits and policies applying to every mission
+func New(builder domain.MarsBuilder, logger *logrus.Logger) *Server {
+	return &Server{
+		builder:      builder,
+		logger:       logger,
+		metrics:      domain.NewMetrics(nil),
+		streamEvents: maxStreamEvents,
+		retained:     maxCompletedMissions,
+		missions:     make(map[string]*Mission),
+		planets:      make(map[string]*planet),
+		done:         make(chan struct{}),
+	}
+}
+
+// Close ends the event streams being served, meant to be called on shutdown (see http.Server.RegisterOnShutdown)
+func (s *Server) Close() {
+	s.close.Do(func() { close(s.done) })
+}
+
+// Wait waits for the missions being explored to complete
+func (s *Server) Wait() {
+	s.running.Wait()
+}
+
+// ServeHTTP routes a request to its handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(path) == 1 && path[0] == "missions":
+		s.allow(w, r, http.MethodPost, s.postMission)
+	case len(path) == 2 && path[0] == "missions":
+		s.allow(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) { s.getMission(w, path[1]) })
+	case len(path) == 3 && path[0] == "missions" && path[2] == "trajectory":
+		s.allow(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) { s.getTrajectory(w, path[1]) })
+	case len(path) == 3 && path[0] == "missions" && path[2] == "events":
+		s.allow(w, r, http.MethodGet, func(w http.ResponseWriter, r *http.Request) { s.getEvents(w, r, path[1]) })
+	case len(path) == 3 && path[0] == "planets" && path[2] == "scents":
+		switch r.Method {
+		case http.MethodGet:
+			s.getScents(w, path[1])
+		case http.MethodDelete:
+			s.deleteScents(w, path[1])
+		default:
+			w.Header().Set("Allow", "GET, DELETE")
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		}
+	case len(path) == 1 && path[0] == "metrics":
+		s.allow(w, r, http.MethodGet, s.getMetrics)
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown path %s", r.URL.Path))
+	}
+}
+
+// allow calls the handler when the request method is the given one
+func (s *Server) allow(w http.ResponseWriter, r *http.Request, method string, handler http.HandlerFunc) {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+		return
+	}
+
+	handler(w, r)
+}
+
+// postMission explores the mission of the request body
+func (s *Server) postMission(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("planet")
+	if name == "" {
+		name = DefaultPlanet
+	}
+
+	lines, err := readMission(r.Header.Get("Content-Type"), http.MaxBytesReader(w, r.Body, maxMissionSize))
+	if err != nil {
+		status := http.StatusBadRequest
+		if err == errUnsupportedMediaType {
+			status = http.StatusUnsupportedMediaType
+		}
+		writeError(w, status, err)
+		return
+	}
+
+	me, err := s.builder.Build(lines)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	mission := s.add(name, me)
+	if r.URL.Query().Get("wait") == "false" {
+		s.running.Add(1)
+		go func() {
+			defer s.running.Done()
+			s.explore(mission, me)
+		}()
+
+		w.Header().Set("Location", "/missions/"+mission.ID)
+		writeJSON(w, http.StatusAccepted, s.status(mission))
+		return
+	}
+
+	s.explore(mission, me)
+	w.Header().Set("Location", "/missions/"+mission.ID)
+	writeJSON(w, http.StatusCreated, s.status(mission))
+}
+
+// add registers a mission to be explored on a planet
+func (s *Server) add(name string, me *domain.MarsExplorer) *Mission {
+	mission := &Mission{
+		Planet:       name,
+		Status:       MissionRunning,
+		trajectories: make([][]domain.Pose, len(me.Robots)),
+		events:       newEventLog(s.streamEvents),
+	}
+	for i := range me.Robots {
+		mission.labels = append(mission.labels, me.Robots[i].Label(i))
+		mission.trajectories[i] = []domain.Pose{me.Robots[i].Pose()}
+	}
+
+	s.mu.Lock()
+	s.next++
+	mission.ID = strconv.Itoa(s.next)
+	s.missions[mission.ID] = mission
+	s.mu.Unlock()
+
+	return mission
+}
+
+// explore sends the mission to its planet, starting with its scents and recording the scents left
+func (s *Server) explore(mission *Mission, me *domain.MarsExplorer) {
+	p := s.planet(mission.Planet)
+	p.mu.Lock()
+	me.Scents = append([]domain.Scent(nil), p.scents...)
+
+	// trajectories are only read once the mission is complete
+	me.SetLogger(domain.NewLogrusLogger(s.logger.WithFields(logrus.Fields{"mission": mission.ID, "planet": mission.Planet})))
+	me.Observe(&missionRecorder{labels: mission.labels, trajectories: mission.trajectories, events: mission.events})
+	s.metrics.Explore(me, me.SendInstructions)
+
+	p.scents = me.Scents
+	p.mu.Unlock()
+
+	robots := make([]domain.RobotReport, 0, len(me.Robots))
+	for i := range me.Robots {
+		robots = append(robots, me.Robots[i].Report())
+	}
+
+	s.mu.Lock()
+	mission.Status = MissionComplete
+	mission.Robots = robots
+	s.evict(mission.ID)
+	s.mu.Unlock()
+
+	s.logger.WithFields(logrus.Fields{"mission": mission.ID, "planet": mission.Planet, "robots": len(me.Robots)}).Info("mission explored")
+	mission.events.append(StreamMissionComplete, s.status(mission))
+	mission.events.close()
+}
+
+// evict records a mission as complete, forgetting the oldest complete missions beyond the ones retained
+// s.mu must be held
+func (s *Server) evict(id string) {
+	s.completed = append(s.completed, id)
+	for len(s.completed) > s.retained {
+		delete(s.missions, s.completed[0])
+		s.completed = s.completed[1:]
+	}
+}
+
+// status returns a copy of the mission safe to be read while the mission is explored
+func (s *Server) status(m *Mission) Mission {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Mission{ID: m.ID, Planet: m.Planet, Status: m.Status, Robots: m.Robots}
+}
+
+// planet returns a planet by name, creating it when unknown, only a mission sent to a planet creating it
+func (s *Server) planet(name string) *planet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.planets[name]
+	if !ok {
+		p = &planet{}
+		s.planets[name] = p
+	}
+
+	return p
+}
+
+// knownPlanet returns a planet by name, a mission having been sent to it
+func (s *Server) knownPlanet(name string) (*planet, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.planets[name]
+	return p, ok
+}
+
+// mission returns a mission by id
+func (s *Server) mission(id string) (*Mission, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.missions[id]
+	return m, ok
+}
+
+// getMission writes the robots status of a mission
+func (s *Server) getMission(w http.ResponseWriter, id string) {
+	m, ok := s.mission(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown mission %s", id))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.status(m))
+}
+
+// getTrajectory writes the poses of every robot of a mission
+func (s *Server) getTrajectory(w http.ResponseWriter, id string) {
+	m, ok := s.mission(id)
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("unknown mission %s", id))
+		return
+	}
+	if s.status(m).Status != MissionComplete {
+		writeError(w, http.StatusConflict, fmt.Errorf("mission %s is still running", id))
+		return
+	}
+
+	trajectories := make([]Trajectory, 0, len(m.trajectories))
+	for i := range m.trajectories {
+		trajectories = append(trajectories, Trajectory{Robot: m.labels[i], Poses: m.trajectories[i]})
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		ID     string       `json:"id"`
+		Robots []Trajectory `json:"robots"`
+	}{ID: m.ID, Robots: trajectories})
+}
+
+// getMetrics writes the statistics of the missions explored in the Prometheus text exposition format
+func (s *Server) getMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := s.metrics.WritePrometheus(w); err != nil {
+		s.logger.WithError(err).Warn("failed to write metrics")
+	}
+}
+
+// getScents writes the scents left on a planet, none for a planet no mission was sent to
+func (s *Server) getScents(w http.ResponseWriter, name string) {
+	scents := make([]domain.ScentSnapshot, 0)
+	if p, ok := s.knownPlanet(name); ok {
+		p.mu.Lock()
+		for _, sc := range p.scents {
+			scents = append(scents, domain.ScentSnapshot{X: sc.PosX, Y: sc.PosY, Direction: sc.Direction})
+		}
+		p.mu.Unlock()
+	}
+
+	writeJSON(w, http.StatusOK, struct {
+		Planet string                 `json:"planet"`
+		Scents []domain.ScentSnapshot `json:"scents"`
+	}{Planet: name, Scents: scents})
+}
+
+// deleteScents removes the scents left on a planet, a planet no mission was sent to having none
+func (s *Server) deleteScents(w http.ResponseWriter, name string) {
+	if p, ok := s.knownPlanet(name); ok {
+		p.mu.Lock()
+		p.scents = nil
+		p.mu.Unlock()
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error as a JSON response, ie: {"error": "unknown mission 3"}
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{Error: err.Error()})
+}
@@ -0,0 +1,378 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/nchagrass/mars-exploration/internal/domain"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const sample = "5 3\n1 1 E\nRFRFRFRF\n\n3 2 N\nFRRFLLFFRRFLL\n\n0 3 W\nLLFFFLFLFL\n"
+
+func newServer(t *testing.T) *httptest.Server {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	ts := httptest.NewServer(New(domain.NewMarsBuilder(domain.NewLogrusLogger(logger)), logger))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func do(t *testing.T, method, url, contentType, body string) (int, string) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	return send(t, req)
+}
+
+func send(t *testing.T, req *http.Request) (int, string) {
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res.StatusCode, strings.TrimSpace(string(data))
+}
+
+func TestServer_postMission(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+		want        string
+	}{
+		{
+			name:        "mission file",
+			contentType: "text/plain",
+			body:        sample,
+			wantStatus:  http.StatusCreated,
+			want: `{"id":"1","planet":"mars","status":"complete","robots":[` +
+				`{"type":"wheeled","x":1,"y":1,"direction":"E","status":"operating"},` +
+				`{"type":"wheeled","x":3,"y":3,"direction":"N","status":"lost","loss":{"cause":"edge","instruction":7,"target":{"x":3,"y":4}}},` +
+				`{"type":"wheeled","x":2,"y":3,"direction":"S","status":"operating"}]}`,
+		},
+		{
+			name:        "JSON mission",
+			contentType: "application/json",
+			body:        `{"surface":{"max_x":5,"max_y":3,"terrain":[{"kind":"rock","x":2,"y":1}]},"robots":[{"id":"scout","x":1,"y":1,"direction":"E","instructions":"FF","energy":10}]}`,
+			wantStatus:  http.StatusCreated,
+			want:        `{"id":"1","planet":"mars","status":"complete","robots":[{"id":"scout","type":"wheeled","x":1,"y":1,"direction":"E","status":"operating","energy":10}]}`,
+		},
+		{
+			name:        "invalid mission",
+			contentType: "text/plain",
+			body:        "5 3\n1 1 E\nFXF\n",
+			wantStatus:  http.StatusBadRequest,
+			want:        `{"error":"failed to load robots instructions, got \"robot #1: command X at index 1 is not supported by wheeled robots\""}`,
+		},
+		{
+			name:        "grid too large",
+			contentType: "text/plain",
+			body:        "60 3\n1 1 E\nF\n",
+			wantStatus:  http.StatusBadRequest,
+			want:        `{"error":"failed to build mars surface, got \"maximum value for the grid execeeded 50\""}`,
+		},
+		{
+			name:        "invalid JSON",
+			contentType: "application/json",
+			body:        `{"robots":`,
+			wantStatus:  http.StatusBadRequest,
+			want:        `{"error":"invalid JSON mission, got \"unexpected EOF\""}`,
+		},
+		{
+			name:        "JSON robot id with a space",
+			contentType: "application/json",
+			body:        `{"surface":{"max_x":5,"max_y":3},"robots":[{"id":"scout 1","x":1,"y":1,"direction":"E","instructions":"F"}]}`,
+			wantStatus:  http.StatusBadRequest,
+			want:        `{"error":"robot #1: invalid id \"scout 1\""}`,
+		},
+		{
+			name:        "JSON robot type adding an option",
+			contentType: "application/json",
+			body:        `{"surface":{"max_x":5,"max_y":3},"robots":[{"type":"hover energy=1","x":1,"y":1,"direction":"E","instructions":"F"}]}`,
+			wantStatus:  http.StatusBadRequest,
+			want:        `{"error":"robot #1: invalid type \"hover energy=1\""}`,
+		},
+		{
+			name:        "JSON direction with several letters",
+			contentType: "application/json",
+			body:        `{"surface":{"max_x":5,"max_y":3},"robots":[{"x":1,"y":1,"direction":"E id=scout","instructions":"F"}]}`,
+			wantStatus:  http.StatusBadRequest,
+			want:        `{"error":"robot #1: invalid direction \"E id=scout\""}`,
+		},
+		{
+			name:        "JSON instructions with a newline",
+			contentType: "application/json",
+			body:        `{"surface":{"max_x":5,"max_y":3},"robots":[{"x":1,"y":1,"direction":"E","instructions":"F\nF"}]}`,
+			wantStatus:  http.StatusBadRequest,
+			want:        `{"error":"robot #1: instructions can't contain whitespace"}`,
+		},
+		{
+			name:        "JSON empty instructions",
+			contentType: "application/json",
+			body:        `{"surface":{"max_x":5,"max_y":3},"robots":[{"x":1,"y":1,"direction":"E","instructions":""}]}`,
+			wantStatus:  http.StatusBadRequest,
+			want:        `{"error":"robot #1: expected instructions"}`,
+		},
+		{
+			name:        "JSON instructions as a comment",
+			contentType: "application/json",
+			body:        `{"surface":{"max_x":5,"max_y":3},"robots":[{"x":1,"y":1,"direction":"E","instructions":"#F"}]}`,
+			wantStatus:  http.StatusBadRequest,
+			want:        `{"error":"robot #1: instructions can't start with #"}`,
+		},
+		{
+			name:        "JSON terrain kind with a space",
+			contentType: "application/json",
+			body:        `{"surface":{"max_x":5,"max_y":3,"terrain":[{"kind":"rock\n1 1","x":2,"y":1}]},"robots":[{"x":1,"y":1,"direction":"E","instructions":"F"}]}`,
+			wantStatus:  http.StatusBadRequest,
+			want:        `{"error":"terrain 1: invalid kind \"rock\\n1 1\""}`,
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "application/xml",
+			body:        "<mission/>",
+			wantStatus:  http.StatusUnsupportedMediaType,
+			want:        `{"error":"unsupported content type, expected text/plain or application/json"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newServer(t)
+
+			status, body := do(t, http.MethodPost, ts.URL+"/missions", tt.contentType, tt.body)
+			if status != tt.wantStatus {
+				t.Errorf("POST /missions got status %d, want %d", status, tt.wantStatus)
+			}
+			if body != tt.want {
+				t.Errorf("POST /missions got %s, want %s", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_getMission(t *testing.T) {
+	ts := newServer(t)
+	do(t, http.MethodPost, ts.URL+"/missions", "text/plain", "5 3\n1 1 E\nRFL\n")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		want       string
+	}{
+		{
+			name:       "result",
+			method:     http.MethodGet,
+			path:       "/missions/1",
+			wantStatus: http.StatusOK,
+			want:       `{"id":"1","planet":"mars","status":"complete","robots":[{"type":"wheeled","x":1,"y":0,"direction":"E","status":"operating"}]}`,
+		},
+		{
+			name:       "trajectory",
+			method:     http.MethodGet,
+			path:       "/missions/1/trajectory",
+			wantStatus: http.StatusOK,
+			want: `{"id":"1","robots":[{"robot":"#1","poses":[` +
+				`{"x":1,"y":1,"direction":"E"},{"x":1,"y":1,"direction":"S"},{"x":1,"y":0,"direction":"S"},{"x":1,"y":0,"direction":"E"}]}]}`,
+		},
+		{
+			name:       "unknown mission",
+			method:     http.MethodGet,
+			path:       "/missions/2",
+			wantStatus: http.StatusNotFound,
+			want:       `{"error":"unknown mission 2"}`,
+		},
+		{
+			name:       "method not allowed",
+			method:     http.MethodDelete,
+			path:       "/missions/1",
+			wantStatus: http.StatusMethodNotAllowed,
+			want:       `{"error":"method DELETE not allowed"}`,
+		},
+		{
+			name:       "unknown path",
+			method:     http.MethodGet,
+			path:       "/robots",
+			wantStatus: http.StatusNotFound,
+			want:       `{"error":"unknown path /robots"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := do(t, tt.method, ts.URL+tt.path, "", "")
+			if status != tt.wantStatus {
+				t.Errorf("%s %s got status %d, want %d", tt.method, tt.path, status, tt.wantStatus)
+			}
+			if body != tt.want {
+				t.Errorf("%s %s got %s, want %s", tt.method, tt.path, body, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_scents(t *testing.T) {
+	ts := newServer(t)
+
+	// the second mission is saved by the scent left by the first one on the same planet only
+	do(t, http.MethodPost, ts.URL+"/missions", "text/plain", "5 3\n3 3 N\nF\n")
+	_, body := do(t, http.MethodPost, ts.URL+"/missions", "text/plain", "5 3\n3 3 N\nFR\n")
+	if !strings.Contains(body, `"x":3,"y":3,"direction":"E","status":"operating"`) {
+		t.Errorf("POST /missions got %s, want the robot saved by the scent", body)
+	}
+	_, body = do(t, http.MethodPost, ts.URL+"/missions?planet=venus", "text/plain", "5 3\n3 3 N\nFR\n")
+	if !strings.Contains(body, `"status":"lost"`) {
+		t.Errorf("POST /missions?planet=venus got %s, want the robot lost", body)
+	}
+
+	status, body := do(t, http.MethodGet, ts.URL+"/planets/mars/scents", "", "")
+	if status != http.StatusOK || body != `{"planet":"mars","scents":[{"x":3,"y":3,"direction":"N"}]}` {
+		t.Errorf("GET /planets/mars/scents got %d %s", status, body)
+	}
+
+	status, _ = do(t, http.MethodDelete, ts.URL+"/planets/mars/scents", "", "")
+	if status != http.StatusNoContent {
+		t.Errorf("DELETE /planets/mars/scents got %d, want %d", status, http.StatusNoContent)
+	}
+
+	_, body = do(t, http.MethodGet, ts.URL+"/planets/mars/scents", "", "")
+	if body != `{"planet":"mars","scents":[]}` {
+		t.Errorf("GET /planets/mars/scents got %s after deleting them", body)
+	}
+}
+
+func TestServer_unknownPlanetScents(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	s := New(domain.NewMarsBuilder(nil), logger)
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	status, body := do(t, http.MethodGet, ts.URL+"/planets/pluto/scents", "", "")
+	if status != http.StatusOK || body != `{"planet":"pluto","scents":[]}` {
+		t.Errorf("GET /planets/pluto/scents got %d %s", status, body)
+	}
+	if status, _ := do(t, http.MethodDelete, ts.URL+"/planets/pluto/scents", "", ""); status != http.StatusNoContent {
+		t.Errorf("DELETE /planets/pluto/scents got %d, want %d", status, http.StatusNoContent)
+	}
+
+	// only a mission sent to a planet keeps track of it
+	s.mu.Lock()
+	planets := len(s.planets)
+	s.mu.Unlock()
+	if planets != 0 {
+		t.Errorf("got %d planets, want none without any mission sent", planets)
+	}
+}
+
+func TestServer_concurrentMissions(t *testing.T) {
+	ts := newServer(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			do(t, http.MethodPost, fmt.Sprintf("%s/missions?planet=p%d", ts.URL, i%3), "text/plain", sample)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 1; i <= 20; i++ {
+		status, body := do(t, http.MethodGet, fmt.Sprintf("%s/missions/%d", ts.URL, i), "", "")
+		var m Mission
+		if status != http.StatusOK || json.Unmarshal([]byte(body), &m) != nil || len(m.Robots) != 3 {
+			t.Errorf("GET /missions/%d got %d %s", i, status, body)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		_, body := do(t, http.MethodGet, fmt.Sprintf("%s/planets/p%d/scents", ts.URL, i), "", "")
+		if body != fmt.Sprintf(`{"planet":"p%d","scents":[{"x":3,"y":3,"direction":"N"}]}`, i) {
+			t.Errorf("GET /planets/p%d/scents got %s", i, body)
+		}
+	}
+}
+
+func TestServer_metrics(t *testing.T) {
+	ts := newServer(t)
+	for i := 0; i < 2; i++ {
+		if status, body := do(t, http.MethodPost, ts.URL+"/missions", "text/plain", sample); status != http.StatusCreated {
+			t.Fatalf("POST /missions got %d %s", status, body)
+		}
+	}
+
+	res, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.StatusCode != http.StatusOK || !strings.HasPrefix(res.Header.Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Errorf("GET /metrics got %d %s", res.StatusCode, res.Header.Get("Content-Type"))
+	}
+	// the second mission doesn't lose its robot, the scent left on the planet by the first one saving it
+	for _, want := range []string{
+		"mars_missions_total 2\n",
+		"mars_robots_deployed_total 6\n",
+		"mars_robots_lost_total{cause=\"edge\"} 1\n",
+		"mars_scent_saves_total 3\n",
+		"mars_simulation_duration_seconds_count 2\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("GET /metrics got\n%s\nwant %s", data, want)
+		}
+	}
+
+	if status, _ := do(t, http.MethodPost, ts.URL+"/metrics", "", ""); status != http.StatusMethodNotAllowed {
+		t.Errorf("POST /metrics got %d, want %d", status, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestServer_evictCompletedMissions(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(ioutil.Discard)
+
+	s := New(domain.NewMarsBuilder(nil), logger)
+	s.retained = 2
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+
+	for i := 0; i < 3; i++ {
+		do(t, http.MethodPost, ts.URL+"/missions", "text/plain", sample)
+	}
+
+	if status, body := do(t, http.MethodGet, ts.URL+"/missions/1", "", ""); status != http.StatusNotFound {
+		t.Errorf("GET /missions/1 got %d %s, want the mission evicted", status, body)
+	}
+	for _, id := range []string{"2", "3"} {
+		if status, body := do(t, http.MethodGet, ts.URL+"/missions/"+id, "", ""); status != http.StatusOK {
+			t.Errorf("GET /missions/%s got %d %s, want the mission kept", id, status, body)
+		}
+	}
+}
@@ -12,9 +12,9 @@ func TestApp_ExploreMars(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	expectedOutput, _ := ioutil.ReadFile("./expectedoutputsample-1.txt")
+	expectedOutput, _ := ioutil.ReadFile("./golden/sample-1.expected")
 
-	bootstrap.New("./inputsample-1.txt")
+	bootstrap.New(bootstrap.Options{InputPath: "./golden/sample-1.input", Config: bootstrap.DefaultConfig()})
 
 	w.Close()
 	out, _ := ioutil.ReadAll(r)
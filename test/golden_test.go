@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"github.com/nchagrass/mars-exploration/internal/bootstrap"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "write the expected reports of the golden missions instead of checking them")
+
+func TestApp_Golden(t *testing.T) {
+	checkGolden(t, "./golden")
+}
+
+// checkGolden explores every <name>.input mission of a directory in parallel, comparing its report with <name>.expected
+// run with -update to write the expected reports instead
+func checkGolden(t *testing.T, dir string) {
+	t.Helper()
+
+	goldens, err := bootstrap.FindGolden(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(goldens) == 0 {
+		t.Fatalf("no golden missions in %s", dir)
+	}
+
+	for _, g := range goldens {
+		g := g
+		t.Run(g.Name, func(t *testing.T) {
+			t.Parallel()
+
+			r := g.Check(*update)
+			if r.Err != nil {
+				t.Fatal(r.Err)
+			}
+			if r.Diff != "" {
+				t.Errorf("unexpected report for %s\n%s", g.Input, r.Diff)
+			}
+		})
+	}
+}